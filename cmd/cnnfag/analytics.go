@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// The analytics tools answer "how unusual is this reading?" with the
+// library's cnnfag.Summarize, cnnfag.PercentileRank and cnnfag.At, so a
+// model does not have to pull the history and do the arithmetic itself.
+
+// seriesProperty is the series argument of get_history and the analytics tools.
+var seriesProperty = map[string]any{
+	"type":        "string",
+	"enum":        append([]string{"index"}, cnnfag.IndicatorIDs...),
+	"description": "\"index\" for the Fear & Greed score, or an indicator ID.",
+}
+
+var statisticsToolDef = map[string]any{
+	"name":         statisticsToolName,
+	"description":  "Summary statistics of CNN's Fear & Greed index or one of its component indicators over a window of up to about a year: count, mean, median and standard deviation, the minimum and maximum with their dates, the latest value with its percentile rank in the window (the percentage of days at or below it), and the number of days spent in each rating band. Use it to tell how unusual the current reading is.",
+	"outputSchema": schemaOf(reflect.TypeOf(statisticsResult{})),
+	"inputSchema": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"series": seriesProperty,
+			"start": map[string]any{
+				"type":        "string",
+				"format":      "date",
+				"description": "First date of the window, YYYY-MM-DD. Defaults to the oldest value.",
+			},
+			"end": map[string]any{
+				"type":        "string",
+				"format":      "date",
+				"description": "Last date of the window, YYYY-MM-DD. Defaults to the latest value.",
+			},
+		},
+		"required":             []string{"series"},
+		"additionalProperties": false,
+	},
+}
+
+var compareToolDef = map[string]any{
+	"name":         compareToolName,
+	"description":  "Compares CNN's Fear & Greed index or one of its component indicators on two dates: the value and rating on each (the previous trading day's when the market was closed), each value's percentile rank in the whole history, the change between them, and summary statistics of the days in between.",
+	"outputSchema": schemaOf(reflect.TypeOf(compareResult{})),
+	"inputSchema": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"series": seriesProperty,
+			"from": map[string]any{
+				"type":        "string",
+				"format":      "date",
+				"description": "Earlier date, YYYY-MM-DD.",
+			},
+			"to": map[string]any{
+				"type":        "string",
+				"format":      "date",
+				"description": "Later date, YYYY-MM-DD. Defaults to the latest value.",
+			},
+		},
+		"required":             []string{"series", "from"},
+		"additionalProperties": false,
+	},
+}
+
+// statisticsResult is what get_statistics returns.
+type statisticsResult struct {
+	Series string `json:"series"`
+	cnnfag.Stats
+}
+
+func getStatistics(ctx context.Context, args toolArgs, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
+	start, end, rerr := toolWindow(args.Start, args.End)
+	if rerr != nil {
+		return nil, rerr
+	}
+	res, err := fetch(ctx)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	series, _ := res.Series(args.Series)
+	values := cnnfag.Between(series, start, end)
+	if len(values) == 0 {
+		return errorResult(fmt.Errorf("no %s values in the requested window", args.Series)), nil
+	}
+	return jsonResult(statisticsResult{Series: args.Series, Stats: cnnfag.Summarize(values)})
+}
+
+// compareResult is what compare_periods returns.
+type compareResult struct {
+	Series string       `json:"series"`
+	From   comparePoint `json:"from"`
+	To     comparePoint `json:"to"`
+	Change float64      `json:"change"`
+	// PercentChange is omitted when the earlier value is zero.
+	PercentChange *float64     `json:"percentChange,omitempty"`
+	Between       cnnfag.Stats `json:"between"`
+}
+
+// comparePoint is one side of a comparison. Date is the date of the value
+// used, which precedes the date asked for when the market was closed.
+type comparePoint struct {
+	cnnfag.Value
+	Percentile float64 `json:"percentile"`
+}
+
+func comparePeriods(ctx context.Context, args toolArgs, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
+	from, to, rerr := toolWindow(args.From, args.To)
+	if rerr != nil {
+		return nil, rerr
+	}
+	res, err := fetch(ctx)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	series, _ := res.Series(args.Series)
+	if len(series) == 0 {
+		return errorResult(fmt.Errorf("no %s values in the requested window", args.Series)), nil
+	}
+
+	a, ok := cnnfag.At(series, from)
+	if !ok {
+		return errorResult(fmt.Errorf("%s is before the oldest %s value, %s", args.From, args.Series, series[0].Date.Format(time.DateOnly))), nil
+	}
+	b := series[len(series)-1]
+	if !to.IsZero() {
+		b, _ = cnnfag.At(series, to)
+	}
+
+	out := compareResult{
+		Series:  args.Series,
+		From:    comparePoint{a, cnnfag.PercentileRank(series, a.Value)},
+		To:      comparePoint{b, cnnfag.PercentileRank(series, b.Value)},
+		Change:  b.Value - a.Value,
+		Between: cnnfag.Summarize(cnnfag.Between(series, a.Date, b.Date)),
+	}
+	if a.Value != 0 {
+		pct := 100 * (b.Value - a.Value) / a.Value
+		out.PercentChange = &pct
+	}
+	return jsonResult(out)
+}
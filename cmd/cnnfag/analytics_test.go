@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestAnalyticsTools(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	res := cnnfag.Result{
+		Score:  40,
+		Rating: "fear",
+		// Friday the 6th to Wednesday the 11th, with the latest point
+		// stamped at CNN's update time.
+		History: []cnnfag.Point{
+			{Date: day(6), Score: 20, Rating: "extreme fear"},
+			{Date: day(9), Score: 50, Rating: "neutral"},
+			{Date: day(10), Score: 80, Rating: "extreme greed"},
+			{Date: day(11).Add(14 * time.Hour), Score: 40, Rating: "fear"},
+		},
+	}
+	fetch := func(context.Context) (cnnfag.Result, error) { return res, nil }
+
+	call := func(name, args string) (map[string]any, *rpcError) {
+		t.Helper()
+		params, _ := json.Marshal(map[string]any{"name": name, "arguments": json.RawMessage(args)})
+		out, rerr := callTool(context.Background(), params, fetch)
+		if rerr != nil {
+			return nil, rerr
+		}
+		result := out.(toolResult)
+		if result.IsError {
+			return map[string]any{"error": result.Content[0].Text}, nil
+		}
+		var v map[string]any
+		if err := json.Unmarshal([]byte(result.Content[0].Text), &v); err != nil {
+			t.Fatal(err)
+		}
+		return v, nil
+	}
+
+	stats, _ := call("get_statistics", `{"series":"index"}`)
+	if stats["count"] != 4.0 || stats["mean"] != 47.5 || stats["median"] != 45.0 || stats["percentile"] != 50.0 {
+		t.Errorf("get_statistics = %v", stats)
+	}
+	if bands := stats["bandDays"].(map[string]any); bands["extreme greed"] != 1.0 || len(bands) != 4 {
+		t.Errorf("bandDays = %v", bands)
+	}
+	if max := stats["max"].(map[string]any); max["value"] != 80.0 || max["date"] != "2026-03-10T00:00:00Z" {
+		t.Errorf("max = %v", max)
+	}
+
+	// end is inclusive, so the window ending on the 11th keeps the point
+	// stamped with the afternoon update.
+	stats, _ = call("get_statistics", `{"series":"index","start":"2026-03-09","end":"2026-03-11"}`)
+	if stats["count"] != 3.0 || stats["mean"] != 170.0/3 {
+		t.Errorf("windowed get_statistics = %v", stats)
+	}
+	if stats, _ = call("get_statistics", `{"series":"vix"}`); stats["error"] == nil {
+		t.Errorf("get_statistics on a missing series = %v", stats)
+	}
+
+	// The 7th is a Saturday: compare_periods uses Friday's close.
+	cmp, _ := call("compare_periods", `{"series":"index","from":"2026-03-07","to":"2026-03-10"}`)
+	from, to := cmp["from"].(map[string]any), cmp["to"].(map[string]any)
+	if from["value"] != 20.0 || from["date"] != "2026-03-06T00:00:00Z" || to["value"] != 80.0 || to["percentile"] != 100.0 {
+		t.Errorf("compare_periods from %v to %v", from, to)
+	}
+	if cmp["change"] != 60.0 || cmp["percentChange"] != 300.0 || cmp["between"].(map[string]any)["count"] != 3.0 {
+		t.Errorf("compare_periods = %v", cmp)
+	}
+	if cmp, _ = call("compare_periods", `{"series":"index","from":"2026-03-09"}`); cmp["to"].(map[string]any)["value"] != 40.0 {
+		t.Errorf("compare_periods to the latest = %v", cmp)
+	}
+	if cmp, _ = call("compare_periods", `{"series":"index","from":"2025-01-01"}`); cmp["error"] == nil {
+		t.Errorf("compare_periods before the history = %v", cmp)
+	}
+	if _, rerr := call("compare_periods", `{"series":"index","from":"2026-03-10","to":"2026-03-09"}`); rerr == nil || rerr.Code != -32602 {
+		t.Errorf("inverted compare_periods = %v", rerr)
+	}
+
+	fetch = func(context.Context) (cnnfag.Result, error) { return cnnfag.Result{}, errors.New("boom") }
+	if stats, _ = call("get_statistics", `{"series":"index"}`); stats["error"] != "boom" {
+		t.Errorf("get_statistics on a fetch error = %v", stats)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image/gif"
+	"io"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+	"github.com/wildsurfer/cnn-fear-and-greed-parse/v2/render"
+)
+
+// runAnimate writes an animated GIF of the gauge moving through the
+// history, one frame per day or per week.
+func runAnimate(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag animate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	since := fs.String("since", "", "first `date` to animate (2006-01-02)")
+	until := fs.String("until", "", "last `date` to animate (2006-01-02)")
+	out := fs.String("out", "", "write the GIF to `path`, or - for stdout")
+	delay := fs.Duration("delay", 100*time.Millisecond, "how long each frame shows")
+	step := fs.String("step", "daily", "one frame per trading day (daily) or per week (weekly)")
+	width := fs.Int("width", 400, "image width in pixels, at most 800")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	from, to, err := dateRange(*since, *until)
+	switch {
+	case err != nil:
+		fmt.Fprintln(stderr, "cnnfag animate:", err)
+		return 2
+	case *out == "":
+		fmt.Fprintln(stderr, "cnnfag animate: -out is required")
+		return 2
+	case *step != "daily" && *step != "weekly":
+		fmt.Fprintf(stderr, "cnnfag animate: unknown step %q\n", *step)
+		return 2
+	case *delay < 10*time.Millisecond:
+		fmt.Fprintln(stderr, "cnnfag animate: -delay must be at least 10ms")
+		return 2
+	case *width < 100 || *width > 800:
+		// Every frame is held in memory until the GIF is encoded, so a
+		// year of daily frames at 800 pixels is already near 100MB.
+		fmt.Fprintln(stderr, "cnnfag animate: -width must be between 100 and 800")
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag animate:", err)
+		return 1
+	}
+
+	var points []cnnfag.Point
+	for _, p := range res.History {
+		if (from.IsZero() || !p.Date.Before(from)) && (to.IsZero() || p.Date.Before(to)) {
+			points = append(points, p)
+		}
+	}
+	if *step == "weekly" {
+		points = lastOfWeek(points)
+	}
+	if len(points) == 0 {
+		fmt.Fprintln(stderr, "cnnfag animate: no history in that range")
+		return 1
+	}
+
+	anim := render.Animation(points, *width, int(*delay/(10*time.Millisecond)))
+	if err := writeOutput(*out, stdout, func(w io.Writer) error { return gif.EncodeAll(w, anim) }); err != nil {
+		fmt.Fprintln(stderr, "cnnfag animate:", err)
+		return 1
+	}
+	return 0
+}
+
+// lastOfWeek keeps the last point of each ISO week, the weekly close.
+func lastOfWeek(points []cnnfag.Point) []cnnfag.Point {
+	var weekly []cnnfag.Point
+	for i, p := range points {
+		if i+1 < len(points) {
+			y, w := p.Date.ISOWeek()
+			ny, nw := points[i+1].Date.ISOWeek()
+			if y == ny && w == nw {
+				continue
+			}
+		}
+		weekly = append(weekly, p)
+	}
+	return weekly
+}
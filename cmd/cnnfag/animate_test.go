@@ -0,0 +1,74 @@
+package main
+
+import (
+	"image/gif"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestLastOfWeek(t *testing.T) {
+	var points []cnnfag.Point
+	// Thursday 2026-01-01 to Wednesday 2026-01-14, weekdays only.
+	for d := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); d.Day() <= 14; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			points = append(points, cnnfag.Point{Date: d})
+		}
+	}
+	var got []string
+	for _, p := range lastOfWeek(points) {
+		got = append(got, p.Date.Format(time.DateOnly))
+	}
+	if want := "2026-01-02 2026-01-09 2026-01-14"; strings.Join(got, " ") != want {
+		t.Errorf("lastOfWeek = %v, want %s", got, want)
+	}
+}
+
+func TestRunAnimate(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	out := filepath.Join(t.TempDir(), "week.gif")
+	var stderr strings.Builder
+	args := []string{"animate", "-since", "2025-08-12", "-out", out, "-delay", "250ms", "-width", "200"}
+	if code := run(args, strings.NewReader(""), new(strings.Builder), &stderr); code != 0 {
+		t.Fatalf("run(%v) = %d, stderr: %s", args, code, stderr.String())
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 || anim.Delay[0] != 25 {
+		t.Errorf("got %d frames with delay %v, want 2 from 2025-08-12 at 25", len(anim.Image), anim.Delay)
+	}
+
+	for _, args := range [][]string{
+		{"animate"},
+		{"animate", "-out", out, "-step", "monthly"},
+		{"animate", "-out", out, "-since", "August"},
+		{"animate", "-out", out, "-width", "801"},
+	} {
+		if code := run(args, strings.NewReader(""), new(strings.Builder), new(strings.Builder)); code != 2 {
+			t.Errorf("run(%v) = %d, want 2", args, code)
+		}
+	}
+	if code := run([]string{"animate", "-out", out, "-since", "2030-01-01"}, strings.NewReader(""), new(strings.Builder), new(strings.Builder)); code != 1 {
+		t.Errorf("animate with an empty range = %d, want 1", code)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// mcpAuth guards the HTTP transport. A request needs a bearer token: one
+// of a static list, or with OAuth a JWT access token issued for this
+// server. As an OAuth 2.1 resource server it publishes protected resource
+// metadata (RFC 9728) naming the authorization server, and a 401 points
+// clients at it, as the MCP authorization spec asks.
+type mcpAuth struct {
+	tokens map[[sha256.Size]byte]bool // hashed, so lookups reveal nothing through timing
+
+	// OAuth; keys is nil without it.
+	keys     *keySet
+	issuer   string
+	resource string // our canonical URI, which tokens must name as audience
+}
+
+// newMCPAuth returns the auth the flags ask for, or nil for none.
+func newMCPAuth(tokenFile, jwks, issuer, resource string) (*mcpAuth, error) {
+	if tokenFile == "" && jwks == "" {
+		if issuer != "" || resource != "" {
+			return nil, errors.New("-issuer and -resource need -jwks")
+		}
+		return nil, nil
+	}
+	a := &mcpAuth{tokens: map[[sha256.Size]byte]bool{}, issuer: issuer, resource: resource}
+	if tokenFile != "" {
+		if err := a.readTokens(tokenFile); err != nil {
+			return nil, err
+		}
+	}
+	if jwks != "" {
+		if issuer == "" || resource == "" {
+			return nil, errors.New("-jwks needs -issuer and -resource")
+		}
+		if u, err := url.Parse(resource); err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+			return nil, fmt.Errorf("-resource %q is not an absolute URI", resource)
+		}
+		keys, err := loadKeySet(jwks)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = keys
+	}
+	return a, nil
+}
+
+// readTokens reads static tokens, one per line; blank lines and lines
+// starting with # are skipped.
+func (a *mcpAuth) readTokens(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if t := strings.TrimSpace(sc.Text()); t != "" && !strings.HasPrefix(t, "#") {
+			a.tokens[sha256.Sum256([]byte(t))] = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if len(a.tokens) == 0 {
+		return fmt.Errorf("%s: no tokens", path)
+	}
+	return nil
+}
+
+// metadataPath is where the protected resource metadata is served: the
+// well-known prefix followed by the resource's path, per RFC 9728.
+func (a *mcpAuth) metadataPath() string {
+	u, _ := url.Parse(a.resource)
+	return "/.well-known/oauth-protected-resource" + strings.TrimRight(u.Path, "/")
+}
+
+func (a *mcpAuth) metadataURL() string {
+	u, _ := url.Parse(a.resource)
+	return u.Scheme + "://" + u.Host + a.metadataPath()
+}
+
+// metadata serves the protected resource metadata.
+func (a *mcpAuth) metadata(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"resource":                 a.resource,
+		"authorization_servers":    []string{a.issuer},
+		"bearer_methods_supported": []string{"header"},
+		"resource_name":            "cnnfag",
+	})
+}
+
+// principalKey is the context key for who a request authenticated as.
+type principalKey struct{}
+
+// principalFrom returns who the request authenticated as: the issuer and
+// subject of a JWT, or the hash of a static token. It is empty without
+// auth.
+func principalFrom(ctx context.Context) string {
+	p, _ := ctx.Value(principalKey{}).(string)
+	return p
+}
+
+// wrap returns next behind the bearer token check. The scheme is matched
+// without regard to case, as HTTP auth schemes are.
+func (a *mcpAuth) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		token = strings.TrimLeft(token, " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			a.challenge(w, nil)
+			return
+		}
+		principal, err := a.authenticate(token)
+		if err != nil {
+			a.challenge(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+// authenticate checks a bearer token and returns its principal.
+func (a *mcpAuth) authenticate(token string) (string, error) {
+	hash := sha256.Sum256([]byte(token))
+	if a.tokens[hash] {
+		return "token:" + hex.EncodeToString(hash[:]), nil
+	}
+	if a.keys == nil {
+		return "", errors.New("unknown token")
+	}
+	claims, err := verifyJWT(token, a.keys)
+	if err != nil {
+		return "", err
+	}
+	t := now()
+	switch {
+	case claims.Issuer != a.issuer:
+		return "", fmt.Errorf("token issued by %q, not %q", claims.Issuer, a.issuer)
+	case !claims.Audience.has(a.resource):
+		// A token meant for another server must not work here, or that
+		// server could replay its clients' tokens against us.
+		return "", fmt.Errorf("token is not for %s", a.resource)
+	case claims.Expires == 0:
+		return "", errors.New("token has no expiry")
+	case t.After(unixTime(claims.Expires).Add(jwtLeeway)):
+		return "", errors.New("token expired")
+	case claims.NotBefore != 0 && t.Add(jwtLeeway).Before(unixTime(claims.NotBefore)):
+		return "", errors.New("token not valid yet")
+	}
+	return "jwt:" + claims.Issuer + " " + claims.Subject, nil
+}
+
+func (aud audience) has(resource string) bool {
+	for _, a := range aud {
+		if strings.TrimRight(a, "/") == strings.TrimRight(resource, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+// challenge answers 401 with a WWW-Authenticate header that carries the
+// reason a token was refused, if one was given, and with OAuth where to
+// find the metadata.
+func (a *mcpAuth) challenge(w http.ResponseWriter, err error) {
+	var params []string
+	if err != nil {
+		params = append(params, `error="invalid_token"`, "error_description="+quoteString(err.Error()))
+	}
+	if a.keys != nil {
+		params = append(params, "resource_metadata="+quoteString(a.metadataURL()))
+	}
+	challenge := "Bearer"
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+// quoteString makes s an HTTP quoted-string, in which only backslash and
+// the double quote are escaped; Go's %q escapes more than HTTP undoes.
+func quoteString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
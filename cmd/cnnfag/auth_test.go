@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestMCPAuth(t *testing.T) {
+	iss := newTestIssuer(t)
+	tokens := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(tokens, []byte("# CI\nstatic-secret\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	const resource = "https://mcp.example/mcp"
+	auth, err := newMCPAuth(tokens, iss.srv.URL, "https://issuer.example", resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetch := func(ctx context.Context) (cnnfag.Result, error) { return cnnfag.Result{Score: 43.71}, nil }
+	h := newMCPHandler(newResultCache(fetch, time.Minute), nil)
+	srv := httptest.NewServer(mcpMux(h, auth))
+	defer srv.Close()
+	defer h.closeAll()
+
+	post := func(token, session, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest("POST", srv.URL+"/mcp", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if session != "" {
+			req.Header.Set("Mcp-Session-Id", session)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	const initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`
+	const ping = `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	exp := time.Now().Add(time.Hour).Unix()
+	jwt := func(claims map[string]any) string {
+		c := map[string]any{"iss": "https://issuer.example", "sub": "alice", "aud": resource, "exp": exp}
+		for k, v := range claims {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return iss.sign("ec1", "ES256", c)
+	}
+	const metadataURL = "https://mcp.example/.well-known/oauth-protected-resource/mcp"
+
+	resp := post("", "", initialize)
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != `Bearer resource_metadata="`+metadataURL+`"` {
+		t.Errorf("no token: %d %q", resp.StatusCode, resp.Header.Get("WWW-Authenticate"))
+	}
+
+	for _, tc := range []struct {
+		name, token string
+		ok          bool
+	}{
+		{"static token", "static-secret", true},
+		{"JWT", jwt(nil), true},
+		{"audience in a list", jwt(map[string]any{"aud": []string{"https://other.example", resource + "/"}}), true},
+		{"unknown static token", "static-secret2", false},
+		{"comment is not a token", "# CI", false},
+		{"other audience", jwt(map[string]any{"aud": "https://other.example/mcp"}), false},
+		{"no audience", jwt(map[string]any{"aud": nil}), false},
+		{"other issuer", jwt(map[string]any{"iss": "https://evil.example"}), false},
+		{"expired", jwt(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}), false},
+		{"no expiry", jwt(map[string]any{"exp": nil}), false},
+		{"not yet valid", jwt(map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}), false},
+	} {
+		resp := post(tc.token, "", initialize)
+		challenge := resp.Header.Get("WWW-Authenticate")
+		switch {
+		case tc.ok && resp.StatusCode != http.StatusOK:
+			t.Errorf("%s: status %d, %q", tc.name, resp.StatusCode, challenge)
+		case !tc.ok && (resp.StatusCode != http.StatusUnauthorized ||
+			!strings.HasPrefix(challenge, `Bearer error="invalid_token", error_description=`) ||
+			!strings.HasSuffix(challenge, `resource_metadata="`+metadataURL+`"`)):
+			t.Errorf("%s: status %d, %q", tc.name, resp.StatusCode, challenge)
+		}
+	}
+
+	// A session belongs to whoever started it.
+	alice := jwt(nil)
+	session := post(alice, "", initialize).Header.Get("Mcp-Session-Id")
+	for name, token := range map[string]string{
+		"static token": "static-secret",
+		"other user":   jwt(map[string]any{"sub": "bob"}),
+	} {
+		if resp := post(token, session, ping); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s using alice's session: status %d", name, resp.StatusCode)
+		}
+	}
+	if resp := post(jwt(nil), session, ping); resp.StatusCode != http.StatusOK {
+		t.Errorf("alice with a fresh token: status %d", resp.StatusCode)
+	}
+
+	// The metadata needs no token.
+	for _, path := range []string{"/.well-known/oauth-protected-resource/mcp", "/.well-known/oauth-protected-resource"} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var meta struct {
+			Resource             string   `json:"resource"`
+			AuthorizationServers []string `json:"authorization_servers"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&meta)
+		resp.Body.Close()
+		if err != nil || meta.Resource != resource || len(meta.AuthorizationServers) != 1 || meta.AuthorizationServers[0] != "https://issuer.example" {
+			t.Errorf("%s: %+v, %v", path, meta, err)
+		}
+	}
+}
+
+func TestMCPAuthStatic(t *testing.T) {
+	tokens := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(tokens, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := newMCPAuth(tokens, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newMCPHandler(newResultCache(nil, time.Minute), nil)
+	srv := httptest.NewServer(mcpMux(h, auth))
+	defer srv.Close()
+	defer h.closeAll()
+
+	resp, err := srv.Client().Post(srv.URL+"/mcp", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+		t.Errorf("no token: %d %q", resp.StatusCode, resp.Header.Get("WWW-Authenticate"))
+	}
+	if resp, err := srv.Client().Get(srv.URL + "/.well-known/oauth-protected-resource"); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("metadata without OAuth: %v %v", resp.StatusCode, err)
+	}
+
+	// The scheme is case-insensitive.
+	for _, header := range []string{"bearer s3cret", "BEARER  s3cret"} {
+		req, _ := http.NewRequest("POST", srv.URL+"/mcp", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", header)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized {
+			t.Errorf("Authorization %q: refused", header)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	auth.challenge(rec, errors.New(`no key "k1" \ é`))
+	if got, want := rec.Header().Get("WWW-Authenticate"), `Bearer error="invalid_token", error_description="no key \"k1\" \\ é"`; got != want {
+		t.Errorf("challenge = %s, want %s", got, want)
+	}
+}
+
+func TestNewMCPAuth(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(empty, []byte("# nothing yet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if auth, err := newMCPAuth("", "", "", ""); auth != nil || err != nil {
+		t.Errorf("no flags: %v, %v", auth, err)
+	}
+	for _, args := range [][4]string{
+		{empty, "", "", ""},
+		{"/no/such/file", "", "", ""},
+		{"", "", "https://issuer.example", ""},
+		{"", "https://issuer.example/jwks", "", "https://mcp.example/mcp"},
+		{"", "https://issuer.example/jwks", "https://issuer.example", ""},
+		{"", "https://issuer.example/jwks", "https://issuer.example", "/mcp"},
+	} {
+		if _, err := newMCPAuth(args[0], args[1], args[2], args[3]); err == nil {
+			t.Errorf("newMCPAuth%q: no error", args)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// resultCache shares one fetch of the index between callers for ttl, so a
+// badge embedded in a busy wiki page costs one request to CNN every few
+// minutes rather than one per view. Concurrent callers with an expired
+// entry wait for a single fetch, each only as long as its own context
+// allows.
+type resultCache struct {
+	fetch func(context.Context) (cnnfag.Result, error)
+	ttl   time.Duration
+
+	mu   sync.Mutex
+	res  cnnfag.Result
+	at   time.Time  // zero until the first successful fetch
+	call *cacheCall // the fetch in flight, nil if none
+}
+
+// cacheCall is one fetch; done is closed once res and err are set.
+type cacheCall struct {
+	done chan struct{}
+	res  cnnfag.Result
+	err  error
+
+	mu     sync.Mutex
+	traces []*cnnfag.Trace // of the callers still waiting
+}
+
+func newResultCache(fetch func(context.Context) (cnnfag.Result, error), ttl time.Duration) *resultCache {
+	return &resultCache{fetch: fetch, ttl: ttl}
+}
+
+// get returns the cached result if it is younger than ttl, and fetches a
+// new one otherwise. Failed fetches are not cached.
+func (c *resultCache) get(ctx context.Context) (cnnfag.Result, error) {
+	res, _, err := c.lookup(ctx, nil)
+	return res, err
+}
+
+// lookup is get that also reports whether the result came from the cache.
+// If it waits for a fetch, trace, which may be nil, sees the fetch from then
+// on and until lookup returns: a caller that gives up hears no more of it.
+func (c *resultCache) lookup(ctx context.Context, trace *cnnfag.Trace) (res cnnfag.Result, cached bool, err error) {
+	c.mu.Lock()
+	if !c.at.IsZero() && now().Sub(c.at) < c.ttl {
+		res = c.res
+		c.mu.Unlock()
+		return res, true, nil
+	}
+	call := c.call
+	if call == nil {
+		call = &cacheCall{done: make(chan struct{})}
+		c.call = call
+		go c.run(ctx, call)
+	}
+	c.mu.Unlock()
+	call.join(trace)
+
+	select {
+	case <-call.done:
+		return call.res, false, call.err
+	case <-ctx.Done():
+		call.leave(trace)
+		return cnnfag.Result{}, false, ctx.Err()
+	}
+}
+
+// run makes the fetch for call. It keeps the deadline of the caller that
+// started it but not its cancellation, since others may be waiting for the
+// result, and reports to the traces of whoever is waiting.
+func (c *resultCache) run(ctx context.Context, call *cacheCall) {
+	fetchCtx := cnnfag.WithTrace(context.Background(), call.trace())
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithDeadline(fetchCtx, deadline)
+		defer cancel()
+	}
+	call.res, call.err = c.fetch(fetchCtx)
+
+	c.mu.Lock()
+	if call.err == nil {
+		c.res, c.at = call.res, now()
+	}
+	c.call = nil
+	c.mu.Unlock()
+	close(call.done)
+}
+
+func (call *cacheCall) join(t *cnnfag.Trace) {
+	if t == nil {
+		return
+	}
+	call.mu.Lock()
+	call.traces = append(call.traces, t)
+	call.mu.Unlock()
+}
+
+// leave stops reporting to t. It waits for a hook that is running, so none
+// runs after it returns.
+func (call *cacheCall) leave(t *cnnfag.Trace) {
+	if t == nil {
+		return
+	}
+	call.mu.Lock()
+	for i, w := range call.traces {
+		if w == t {
+			call.traces = append(call.traces[:i], call.traces[i+1:]...)
+			break
+		}
+	}
+	call.mu.Unlock()
+}
+
+// trace returns the Trace the fetch reports to, which passes each event
+// on to the traces of the callers waiting at the time.
+func (call *cacheCall) trace() *cnnfag.Trace {
+	each := func(hook func(*cnnfag.Trace)) {
+		call.mu.Lock()
+		defer call.mu.Unlock()
+		for _, t := range call.traces {
+			hook(t)
+		}
+	}
+	return &cnnfag.Trace{
+		SourceStart: func(name string) {
+			each(func(t *cnnfag.Trace) {
+				if t.SourceStart != nil {
+					t.SourceStart(name)
+				}
+			})
+		},
+		SourceDone: func(name string, err error) {
+			each(func(t *cnnfag.Trace) {
+				if t.SourceDone != nil {
+					t.SourceDone(name, err)
+				}
+			})
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestResultCache(t *testing.T) {
+	clock := time.Date(2026, 8, 13, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	calls := 0
+	var fail error
+	c := newResultCache(func(context.Context) (cnnfag.Result, error) {
+		calls++
+		return cnnfag.Result{Score: float64(calls)}, fail
+	}, time.Minute)
+	get := func() (float64, error) {
+		res, err := c.get(context.Background())
+		return res.Score, err
+	}
+
+	if score, _ := get(); score != 1 {
+		t.Fatalf("first get = %v, want 1", score)
+	}
+	clock = clock.Add(30 * time.Second)
+	if score, _ := get(); score != 1 || calls != 1 {
+		t.Errorf("get within the ttl = %v after %d fetches, want the cached 1", score, calls)
+	}
+
+	clock = clock.Add(time.Minute)
+	fail = errors.New("down")
+	if _, err := get(); err == nil {
+		t.Error("failed fetch after the ttl returned no error")
+	}
+	fail = nil
+	if score, _ := get(); score != 3 {
+		t.Errorf("get after a failed fetch = %v, want a fresh 3", score)
+	}
+}
+
+func TestResultCacheConcurrent(t *testing.T) {
+	// A slow fetch that the test releases.
+	started, release := make(chan struct{}), make(chan struct{})
+	calls := 0
+	c := newResultCache(func(ctx context.Context) (cnnfag.Result, error) {
+		calls++
+		close(started)
+		select {
+		case <-release:
+			return cnnfag.Result{Score: 42}, nil
+		case <-ctx.Done():
+			return cnnfag.Result{}, ctx.Err()
+		}
+	}, time.Minute)
+
+	// The caller that starts the fetch gives up; the fetch goes on.
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := c.get(first)
+		firstErr <- err
+	}()
+	<-started
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller: err = %v, want context.Canceled", err)
+	}
+
+	// A waiter gives up at its own deadline without holding anyone up.
+	short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.get(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiter past its deadline: err = %v", err)
+	}
+
+	results := make(chan float64, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			res, _ := c.get(context.Background())
+			results <- res.Score
+		}()
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		if score := <-results; score != 42 {
+			t.Errorf("waiter %d got %v, want 42", i, score)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1", calls)
+	}
+}
+
+// heldTransport serves body once the test closes release, and closes
+// started when the first request arrives.
+type heldTransport struct {
+	body             []byte
+	started, release chan struct{}
+}
+
+func (t heldTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	close(t.started)
+	<-t.release
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(t.body)), Header: make(http.Header)}, nil
+}
+
+func TestResultCacheTrace(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := heldTransport{fixture, make(chan struct{}), make(chan struct{})}
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: held}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	c := newResultCache(cnnfag.Get, time.Minute)
+	var mu sync.Mutex
+	events := map[string][]string{}
+	trace := func(who string) *cnnfag.Trace {
+		record := func(event string) {
+			mu.Lock()
+			events[who] = append(events[who], event)
+			mu.Unlock()
+		}
+		return &cnnfag.Trace{
+			SourceStart: func(name string) { record("start " + name) },
+			SourceDone:  func(name string, err error) { record("done " + name) },
+		}
+	}
+
+	// The first caller starts the fetch, then gives up while a second
+	// waits on it.
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		c.lookup(first, trace("first"))
+		close(firstDone)
+	}()
+	<-held.started
+	call := c.call
+	secondDone := make(chan struct{})
+	go func() {
+		c.lookup(context.Background(), trace("second"))
+		close(secondDone)
+	}()
+	for joined := false; !joined; {
+		call.mu.Lock()
+		joined = len(call.traces) == 2
+		call.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	cancelFirst()
+	<-firstDone
+	close(held.release)
+	<-secondDone
+
+	want := map[string][]string{
+		"first":  {"start graphdata"},
+		"second": {"done graphdata"},
+	}
+	for who, w := range want {
+		if got := events[who]; len(got) != len(w) || got[0] != w[0] {
+			t.Errorf("%s caller saw %q, want %q", who, got, w)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// runChart draws the history of the index or of one indicator in the
+// terminal, as a braille line chart or, with -spark, a one-line sparkline.
+func runChart(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag chart", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	indicator := fs.String("indicator", "index", "series to chart: index or one of "+strings.Join(cnnfag.IndicatorIDs, ", "))
+	width := fs.Int("width", 60, "chart width in characters; with -spark, the number of days")
+	height := fs.Int("height", 12, "chart height in lines")
+	spark := fs.Bool("spark", false, "print a one-line sparkline instead, for status bars")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !validSeries(*indicator) {
+		fmt.Fprintf(stderr, "cnnfag chart: unknown indicator %q\n", *indicator)
+		return 2
+	}
+	if *width < 2 || *height < 2 {
+		fmt.Fprintln(stderr, "cnnfag chart: -width and -height must be at least 2")
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag chart:", err)
+		return 1
+	}
+	series, _ := res.Series(*indicator)
+	if len(series) == 0 {
+		fmt.Fprintln(stderr, "cnnfag chart: no history to chart")
+		return 1
+	}
+
+	color := useColor(stdout)
+	if *spark {
+		fmt.Fprintln(stdout, sparkline(series, *width, *indicator == "index", color))
+		return 0
+	}
+	fmt.Fprint(stdout, lineChart(series, *width, *height, *indicator == "index", color))
+	return 0
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the last n values as block characters followed by the
+// latest value. Index scores are drawn on the fixed 0-100 scale so the
+// height means the same every day; raw indicator values use their range.
+func sparkline(series []cnnfag.Value, n int, scaled, color bool) string {
+	if len(series) > n {
+		series = series[len(series)-n:]
+	}
+	lo, hi := 0.0, 100.0
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }
+	if !scaled {
+		lo, hi = valueRange(series)
+		format = formatRaw
+	}
+
+	var b strings.Builder
+	for _, v := range series {
+		i := int(math.Round((v.Value - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		block := string(sparkBlocks[min(max(i, 0), len(sparkBlocks)-1)])
+		if color {
+			block = colorize(v.Rating, block)
+		}
+		b.WriteString(block)
+	}
+	last := series[len(series)-1]
+	fmt.Fprintf(&b, " %s", format(last.Value))
+	return b.String()
+}
+
+func valueRange(series []cnnfag.Value) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range series {
+		lo, hi = math.Min(lo, v.Value), math.Max(hi, v.Value)
+	}
+	if lo == hi {
+		lo, hi = lo-1, hi+1
+	}
+	return lo, hi
+}
+
+// braille is a canvas of braille characters, each a 2x4 grid of dots.
+type braille struct {
+	w, h  int // in characters
+	cells [][]uint8
+}
+
+func newBraille(w, h int) *braille {
+	b := &braille{w: w, h: h, cells: make([][]uint8, h)}
+	for i := range b.cells {
+		b.cells[i] = make([]uint8, w)
+	}
+	return b
+}
+
+// brailleDots maps a dot's position in its cell, [row][column], to its bit
+// in the U+2800 block.
+var brailleDots = [4][2]uint8{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// set turns on the dot at x, y, counted from the top left.
+func (b *braille) set(x, y int) {
+	if x < 0 || y < 0 || x >= b.w*2 || y >= b.h*4 {
+		return
+	}
+	b.cells[y/4][x/2] |= brailleDots[y%4][x%2]
+}
+
+// line draws from x0, y0 to x1, y1 with Bresenham's algorithm.
+func (b *braille) line(x0, y0, x1, y1 int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	for e := dx + dy; ; {
+		b.set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func (b *braille) row(y int) string {
+	r := make([]rune, b.w)
+	for x, c := range b.cells[y] {
+		r[x] = rune(0x2800 + int(c))
+	}
+	return string(r)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// lineChart draws series as a braille line chart with a value axis on the
+// left and the first and last dates below. For the index the scale is 0-100
+// and dotted lines separate CNN's rating bands, each named on the right and
+// colored when color is on. The latest value is marked at the right end.
+func lineChart(series []cnnfag.Value, width, height int, bands, color bool) string {
+	lo, hi := 0.0, 100.0
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }
+	if !bands {
+		lo, hi = valueRange(series)
+		pad := (hi - lo) * 0.05
+		lo, hi = lo-pad, hi+pad
+		format = formatRaw
+	}
+	dotsX, dotsY := width*2, height*4
+	yOf := func(v float64) int { return int(math.Round((hi - v) / (hi - lo) * float64(dotsY-1))) }
+	xOf := func(i int) int {
+		if len(series) == 1 {
+			return dotsX - 1
+		}
+		return i * (dotsX - 1) / (len(series) - 1)
+	}
+	valueAt := func(row int) float64 { return hi - (float64(row)+0.5)/float64(height)*(hi-lo) }
+
+	canvas := newBraille(width, height)
+	labels := make([]string, height)
+	if bands {
+		for _, band := range cnnfag.Bands[1:] {
+			for x := 0; x < dotsX; x += 4 {
+				canvas.set(x, yOf(band.Min))
+			}
+		}
+		for _, band := range cnnfag.Bands {
+			labels[yOf((band.Min+band.Max)/2)/4] = band.Rating
+		}
+	}
+	for i := 1; i < len(series); i++ {
+		canvas.line(xOf(i-1), yOf(series[i-1].Value), xOf(i), yOf(series[i].Value))
+	}
+	last := series[len(series)-1]
+	canvas.set(xOf(len(series)-1), yOf(last.Value))
+	lastRow := yOf(last.Value) / 4
+	labels[lastRow] = fmt.Sprintf("◀ %s (%s)", format(last.Value), last.Rating)
+
+	axis := []string{format(hi), format((hi + lo) / 2), format(lo)}
+	axisWidth := 0
+	for _, a := range axis {
+		axisWidth = max(axisWidth, len(a))
+	}
+
+	var b strings.Builder
+	for row := 0; row < height; row++ {
+		tick := ""
+		switch row {
+		case 0:
+			tick = axis[0]
+		case height / 2:
+			tick = axis[1]
+		case height - 1:
+			tick = axis[2]
+		}
+		line := canvas.row(row)
+		label := labels[row]
+		if color {
+			rating := cnnfag.RatingOf(valueAt(row))
+			if !bands {
+				rating = ""
+			}
+			line = colorize(rating, line)
+			if row == lastRow {
+				label = colorize(last.Rating, label)
+			}
+		}
+		fmt.Fprintf(&b, "%*s ┤%s %s", axisWidth, tick, line, label)
+		b.WriteString("\n")
+	}
+
+	first := series[0].Date.Format(time.DateOnly)
+	end := last.Date.Format(time.DateOnly)
+	fmt.Fprintf(&b, "%*s └%s\n", axisWidth, "", strings.Repeat("─", width))
+	gap := max(width-len(first)-len(end), 1)
+	fmt.Fprintf(&b, "%*s  %s%s%s\n", axisWidth, "", first, strings.Repeat(" ", gap), end)
+	return strings.ReplaceAll(b.String(), " \n", "\n")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestSparkline(t *testing.T) {
+	var series []cnnfag.Value
+	for _, v := range []float64{0, 10, 50, 90, 100} {
+		series = append(series, cnnfag.Value{Value: v, Rating: cnnfag.RatingOf(v)})
+	}
+
+	if got, want := sparkline(series, 30, true, false), "▁▂▅▇█ 100"; got != want {
+		t.Errorf("sparkline = %q, want %q", got, want)
+	}
+	// Only the last n days are drawn, and raw series use their own range.
+	if got, want := sparkline(series[2:], 2, false, false), "▁█ 100.00"; got != want {
+		t.Errorf("sparkline of the last 2 = %q, want %q", got, want)
+	}
+	if got := sparkline(series[4:], 1, true, true); got != "\x1b[1;32m█\x1b[0m 100" {
+		t.Errorf("colored sparkline = %q", got)
+	}
+}
+
+func TestLineChart(t *testing.T) {
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	var series []cnnfag.Value
+	for i, v := range []float64{20, 40, 60, 80, 64} {
+		series = append(series, cnnfag.Value{Date: day.AddDate(0, 0, i), Value: v, Rating: cnnfag.RatingOf(v)})
+	}
+
+	chart := lineChart(series, 20, 8, true, false)
+	lines := strings.Split(strings.TrimSuffix(chart, "\n"), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 8 rows, an axis and dates:\n%s", len(lines), chart)
+	}
+	for _, want := range []string{"◀ 64 (greed)", "extreme fear", "extreme greed", "2026-01-05", "2026-01-09", "100 ┤", "  0 ┤"} {
+		if !strings.Contains(chart, want) {
+			t.Errorf("chart lacks %q:\n%s", want, chart)
+		}
+	}
+	if !strings.ContainsAny(chart, "⠁⠂⠄⡀⠈⠐⠠⢀") {
+		t.Errorf("chart has no braille dots:\n%s", chart)
+	}
+	if strings.Contains(chart, "\x1b[") {
+		t.Error("uncolored chart contains escape codes")
+	}
+
+	// Raw series are scaled to their own range and carry no band labels.
+	raw := lineChart(series, 20, 4, false, true)
+	if strings.Contains(raw, "extreme") || !strings.Contains(raw, "◀ 64.00 (greed)") {
+		t.Errorf("raw chart:\n%s", raw)
+	}
+}
+
+func TestRunChart(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	var stdout, stderr strings.Builder
+	if code := run([]string{"chart", "-indicator", "vix", "-spark"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(chart -spark) = %d, stderr: %s", code, stderr.String())
+	}
+	if got, want := stdout.String(), "█▂▁ 14.49\n"; got != want {
+		t.Errorf("vix sparkline = %q, want %q", got, want)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"chart", "-width", "30", "-height", "6"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(chart) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "◀ 63 (greed)") {
+		t.Errorf("chart output:\n%s", stdout.String())
+	}
+
+	for _, args := range [][]string{{"chart", "-indicator", "gold"}, {"chart", "-width", "1"}} {
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+	}
+}
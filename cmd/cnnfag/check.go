@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Exit codes of cnnfag check. Plain checks follow grep: 0 when the
+// condition holds, 1 when it does not, 2 on errors. Nagios and Icinga
+// define the -nagios ones.
+const (
+	checkMet    = 0
+	checkNotMet = 1
+	checkError  = 2
+
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// runCheck tests the index or an indicator against thresholds for scripts
+// and monitoring. All given conditions must hold for the check to match.
+func runCheck(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	below := fs.String("below", "", "match when the score is below this `number`")
+	above := fs.String("above", "", "match when the score is above this `number`")
+	rating := fs.String("rating", "", "match when the rating is one of these comma-separated `ratings`")
+	indicator := fs.String("indicator", "index", "series to check: index or one of "+strings.Join(cnnfag.IndicatorIDs, ", "))
+	raw := fs.Bool("raw", false, "with -indicator, compare the latest raw value (the VIX level, say) instead of the 0-100 score")
+	nagios := fs.Bool("nagios", false, "print Nagios/Icinga plugin output and exit 0 OK, 1 WARNING, 2 CRITICAL or 3 UNKNOWN")
+	severity := fs.String("severity", "critical", "with -nagios, the state when the condition matches: warning or critical")
+	if err := fs.Parse(args); err != nil {
+		return checkError
+	}
+
+	c, err := newCheck(*below, *above, *rating, *indicator, *raw)
+	if err == nil && *severity != "warning" && *severity != "critical" {
+		err = fmt.Errorf("unknown severity %q", *severity)
+	}
+	if err != nil {
+		if *nagios {
+			fmt.Fprintln(stdout, "FNG UNKNOWN -", err)
+			return nagiosUnknown
+		}
+		fmt.Fprintln(stderr, "cnnfag check:", err)
+		return checkError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err == nil {
+		err = c.available(res)
+	}
+	if err != nil {
+		if *nagios {
+			// Plugins print one line; Get's errors can span several.
+			fmt.Fprintln(stdout, "FNG UNKNOWN -", strings.ReplaceAll(err.Error(), "\n", "; "))
+			return nagiosUnknown
+		}
+		fmt.Fprintln(stderr, "cnnfag check:", err)
+		return checkError
+	}
+
+	met, reason := c.evaluate(res)
+	if !*nagios {
+		if met {
+			fmt.Fprintln(stdout, "met:", reason)
+			return checkMet
+		}
+		fmt.Fprintln(stdout, "not met:", reason)
+		return checkNotMet
+	}
+
+	state, code := "OK", nagiosOK
+	if met {
+		state, code = "CRITICAL", nagiosCritical
+		if *severity == "warning" {
+			state, code = "WARNING", nagiosWarning
+		}
+	}
+	fmt.Fprintf(stdout, "FNG %s - %s | %s\n", state, reason, c.perfdata(res, *severity))
+	return code
+}
+
+// check is a parsed set of cnnfag check conditions.
+type check struct {
+	below, above float64 // NaN when not set
+	ratings      []string
+	indicator    string
+	raw          bool
+}
+
+func newCheck(below, above, ratings, indicator string, raw bool) (*check, error) {
+	c := &check{below: math.NaN(), above: math.NaN(), indicator: indicator, raw: raw}
+	var err error
+	if below != "" {
+		if c.below, err = strconv.ParseFloat(below, 64); err != nil {
+			return nil, fmt.Errorf("-below %q is not a number", below)
+		}
+	}
+	if above != "" {
+		if c.above, err = strconv.ParseFloat(above, 64); err != nil {
+			return nil, fmt.Errorf("-above %q is not a number", above)
+		}
+	}
+	if ratings != "" {
+		for _, r := range strings.Split(ratings, ",") {
+			r = strings.TrimSpace(strings.ToLower(r))
+			if !validRating(r) {
+				return nil, fmt.Errorf("unknown rating %q", r)
+			}
+			c.ratings = append(c.ratings, r)
+		}
+	}
+	switch {
+	case below == "" && above == "" && ratings == "":
+		return nil, errors.New("give at least one of -below, -above and -rating")
+	case !validSeries(indicator):
+		return nil, fmt.Errorf("unknown indicator %q", indicator)
+	case raw && indicator == "index":
+		return nil, errors.New("-raw needs -indicator: the index has no raw value")
+	}
+	return c, nil
+}
+
+func validRating(rating string) bool {
+	for _, b := range cnnfag.Bands {
+		if b.Rating == rating {
+			return true
+		}
+	}
+	return false
+}
+
+// available reports an error if res lacks the value the check needs.
+func (c *check) available(res cnnfag.Result) error {
+	if c.raw {
+		if ind, _ := res.Indicator(c.indicator); len(ind.History) == 0 {
+			return fmt.Errorf("no raw %s value in the result", c.indicator)
+		}
+	}
+	return nil
+}
+
+// value returns the checked value, its rating and its name in messages.
+func (c *check) value(res cnnfag.Result) (float64, string, string) {
+	if c.indicator == "index" {
+		return res.Score, res.Rating, "score"
+	}
+	ind, _ := res.Indicator(c.indicator)
+	if c.raw {
+		return ind.History[len(ind.History)-1].Value, ind.Rating, c.indicator
+	}
+	return ind.Score, ind.Rating, c.indicator + " score"
+}
+
+// evaluate tests res and explains the outcome in one line, for example
+// "score 18.2 is below 25".
+func (c *check) evaluate(res cnnfag.Result) (bool, string) {
+	v, rating, name := c.value(res)
+	format := func(x float64) string { return strconv.FormatFloat(x, 'f', -1, 64) }
+	shown := strconv.FormatFloat(v, 'f', 1, 64)
+	if c.raw {
+		shown = formatRaw(v)
+	}
+
+	met := true
+	var reasons []string
+	test := func(ok bool, yes, no string) {
+		met = met && ok
+		if ok {
+			reasons = append(reasons, yes)
+		} else {
+			reasons = append(reasons, no)
+		}
+	}
+	if !math.IsNaN(c.below) {
+		test(v < c.below, name+" "+shown+" is below "+format(c.below), name+" "+shown+" is not below "+format(c.below))
+	}
+	if !math.IsNaN(c.above) {
+		test(v > c.above, name+" "+shown+" is above "+format(c.above), name+" "+shown+" is not above "+format(c.above))
+	}
+	if len(c.ratings) > 0 {
+		in := false
+		for _, r := range c.ratings {
+			in = in || r == rating
+		}
+		want := strings.Join(c.ratings, " or ")
+		test(in, "rating is "+rating, "rating "+rating+" is not "+want)
+	}
+	return met, strings.Join(reasons, ", ")
+}
+
+// perfdata formats the value as Nagios performance data, with the
+// threshold in Nagios range syntax in the warning or critical field.
+func (c *check) perfdata(res cnnfag.Result, severity string) string {
+	v, _, name := c.value(res)
+	label := strings.ReplaceAll(name, " ", "_")
+	format := func(x float64) string { return strconv.FormatFloat(x, 'f', -1, 64) }
+
+	// Nagios alerts when the value is outside a range, or inside one
+	// prefixed with @. Range ends are inclusive, which matches -below and
+	// -above alone, but an @ range cannot exclude its ends: at exactly
+	// -above or -below, the check is not met while a tool reading the
+	// perfdata would alert. No range syntax says "strictly between".
+	var threshold string
+	switch below, above := !math.IsNaN(c.below), !math.IsNaN(c.above); {
+	case below && above && c.above < c.below:
+		threshold = "@" + format(c.above) + ":" + format(c.below)
+	case below && !above:
+		threshold = format(c.below) + ":"
+	case above && !below:
+		threshold = "~:" + format(c.above)
+	}
+	warn, crit := "", threshold
+	if severity == "warning" {
+		warn, crit = threshold, ""
+	}
+	limits := ";0;100"
+	if c.raw {
+		limits = ";;"
+	}
+	return fmt.Sprintf("%s=%s;%s;%s%s", label, format(math.Round(v*100)/100), warn, crit, limits)
+}
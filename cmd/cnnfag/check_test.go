@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunCheck(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	// The fixture's score is 64.37 (greed); its VIX indicator scores 50
+	// on a raw VIX of 14.49.
+	for _, tc := range []struct {
+		args []string
+		code int
+		out  string
+	}{
+		{[]string{"-below", "25"}, 1, "not met: score 64.4 is not below 25\n"},
+		{[]string{"-above", "60"}, 0, "met: score 64.4 is above 60\n"},
+		{[]string{"-rating", "greed, extreme greed"}, 0, "met: rating is greed\n"},
+		{[]string{"-rating", "fear", "-above", "60"}, 1, "not met: score 64.4 is above 60, rating greed is not fear\n"},
+		{[]string{"-indicator", "vix", "-raw", "-below", "15"}, 0, "met: vix 14.49 is below 15\n"},
+		{[]string{"-nagios", "-below", "25"}, 0, "FNG OK - score 64.4 is not below 25 | score=64.37;;25:;0;100\n"},
+		{[]string{"-nagios", "-above", "60", "-severity", "warning"}, 1, "FNG WARNING - score 64.4 is above 60 | score=64.37;~:60;;0;100\n"},
+		{[]string{"-nagios", "-above", "60", "-below", "70"}, 2, "FNG CRITICAL - score 64.4 is below 70, score 64.4 is above 60 | score=64.37;;@60:70;0;100\n"},
+		// At a threshold the check is not met, as the perfdata agrees for
+		// one-sided ranges; the two-sided @ range includes its ends.
+		{[]string{"-nagios", "-indicator", "vix", "-raw", "-below", "14.49"}, 0, "FNG OK - vix 14.49 is not below 14.49 | vix=14.49;;14.49:;;\n"},
+		{[]string{"-nagios", "-indicator", "vix", "-raw", "-above", "14.49"}, 0, "FNG OK - vix 14.49 is not above 14.49 | vix=14.49;;~:14.49;;\n"},
+		{[]string{"-nagios", "-indicator", "vix", "-raw", "-above", "14.49", "-below", "20"}, 0, "FNG OK - vix 14.49 is below 20, vix 14.49 is not above 14.49 | vix=14.49;;@14.49:20;;\n"},
+		{[]string{"-nagios", "-indicator", "vix", "-raw", "-above", "20"}, 0, "FNG OK - vix 14.49 is not above 20 | vix=14.49;;~:20;;\n"},
+		{[]string{"-nagios"}, 3, "FNG UNKNOWN - give at least one of -below, -above and -rating\n"},
+		{[]string{"-nagios", "-below", "25", "-severity", "page"}, 3, "FNG UNKNOWN - unknown severity \"page\"\n"},
+		{[]string{}, 2, ""},
+		{[]string{"-rating", "panic"}, 2, ""},
+		{[]string{"-raw", "-below", "1"}, 2, ""},
+		{[]string{"-below", "low"}, 2, ""},
+	} {
+		var stdout strings.Builder
+		code := run(append([]string{"check"}, tc.args...), strings.NewReader(""), &stdout, new(strings.Builder))
+		if code != tc.code || stdout.String() != tc.out {
+			t.Errorf("check %v = %d %q, want %d %q", tc.args, code, stdout.String(), tc.code, tc.out)
+		}
+	}
+
+	cnnfag.HTTPClient = &http.Client{Transport: errorTransport{}}
+	var stdout strings.Builder
+	if code := run([]string{"check", "-nagios", "-below", "25"}, strings.NewReader(""), &stdout, new(strings.Builder)); code != 3 || strings.Count(stdout.String(), "\n") != 1 {
+		t.Errorf("nagios check with CNN down = %d %q, want 3 and one line", code, stdout.String())
+	}
+	if code := run([]string{"check", "-below", "25"}, strings.NewReader(""), new(strings.Builder), new(strings.Builder)); code != 2 {
+		t.Errorf("check with CNN down = %d, want 2", code)
+	}
+}
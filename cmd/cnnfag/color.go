@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// ANSI colors for CNN's rating bands, red through green as on CNN's gauge.
+var ratingColors = map[string]string{
+	"extreme fear":  "\x1b[1;31m",
+	"fear":          "\x1b[31m",
+	"neutral":       "\x1b[33m",
+	"greed":         "\x1b[32m",
+	"extreme greed": "\x1b[1;32m",
+}
+
+const colorReset = "\x1b[0m"
+
+// useColor reports whether output to w should be colored: w must be a
+// terminal and NO_COLOR (https://no-color.org) unset.
+func useColor(w io.Writer) bool {
+	return os.Getenv("NO_COLOR") == "" && isTerminal(w)
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps text in the color of rating, or returns it unchanged if the
+// rating has none.
+func colorize(rating, text string) string {
+	c, ok := ratingColors[rating]
+	if !ok {
+		return text
+	}
+	return c + text + colorReset
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// runGauge prints the terminal version of the gauge on CNN's page: the 0-100
+// scale split into rating bands, the needle at the current score, and
+// markers for the previous close, a week ago and a month ago.
+func runGauge(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag gauge", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	width := fs.Int("width", 60, "gauge width in characters")
+	ascii := fs.Bool("ascii", false, "draw with ASCII only, for terminals without Unicode")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *width < 20 {
+		fmt.Fprintln(stderr, "cnnfag gauge: -width must be at least 20")
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag gauge:", err)
+		return 1
+	}
+	fmt.Fprint(stdout, gauge(res, *width, useColor(stdout), *ascii))
+	return 0
+}
+
+// gaugeGlyphs are the characters a gauge is drawn with.
+type gaugeGlyphs struct {
+	needle, fill, plain, boundary, sep string
+}
+
+var (
+	unicodeGlyphs = gaugeGlyphs{needle: "▼", fill: "█", plain: "─", boundary: "┼", sep: "·"}
+	asciiGlyphs   = gaugeGlyphs{needle: "v", fill: "#", plain: "-", boundary: "|", sep: "-"}
+)
+
+// Short band names for zones too narrow for the full one.
+var bandAbbrev = map[string]string{
+	"extreme fear":  "x-fear",
+	"fear":          "fear",
+	"neutral":       "neut",
+	"greed":         "greed",
+	"extreme greed": "x-greed",
+}
+
+// gauge renders res as a horizontal gauge width characters wide. With color
+// each band is a solid block in its color; without, the bands are a line
+// with ticks at the boundaries, so the gauge reads the same on a
+// monochrome terminal or in a log file.
+func gauge(res cnnfag.Result, width int, color, ascii bool) string {
+	g := unicodeGlyphs
+	if ascii {
+		g = asciiGlyphs
+	}
+	col := func(score float64) int {
+		return min(max(int(math.Round(score/100*float64(width-1))), 0), width-1)
+	}
+
+	var b strings.Builder
+
+	// The needle, labeled on whichever side has room.
+	label := fmt.Sprintf("%.0f %s", res.Score, res.Rating)
+	needle := col(res.Score)
+	left := needle+2+len(label) > width
+	if color {
+		label = colorize(res.Rating, label)
+	}
+	if left {
+		pad := max(needle-len(fmt.Sprintf("%.0f %s", res.Score, res.Rating))-1, 0)
+		fmt.Fprintf(&b, "%s%s %s\n", strings.Repeat(" ", pad), label, g.needle)
+	} else {
+		fmt.Fprintf(&b, "%s%s %s\n", strings.Repeat(" ", needle), g.needle, label)
+	}
+
+	// The scale, one color run per band.
+	if color {
+		for _, band := range cnnfag.Bands {
+			from, to := col(band.Min), col(band.Max)
+			if band.Max == 100 {
+				to = width
+			}
+			b.WriteString(colorize(band.Rating, strings.Repeat(g.fill, to-from)))
+		}
+	} else {
+		boundaries := map[int]bool{}
+		for _, band := range cnnfag.Bands[1:] {
+			boundaries[col(band.Min)] = true
+		}
+		for i := 0; i < width; i++ {
+			if boundaries[i] {
+				b.WriteString(g.boundary)
+			} else {
+				b.WriteString(g.plain)
+			}
+		}
+	}
+	b.WriteString("\n")
+
+	// Past-period markers; the more recent wins a shared column.
+	markers := []byte(strings.Repeat(" ", width))
+	for _, m := range []struct {
+		score float64
+		mark  byte
+	}{{res.OneMonthAgo, 'm'}, {res.OneWeekAgo, 'w'}, {res.PreviousClose, 'c'}} {
+		if m.score > 0 {
+			markers[col(m.score)] = m.mark
+		}
+	}
+	b.WriteString(strings.TrimRight(string(markers), " ") + "\n")
+
+	// Band names, centered in their zones.
+	names := []byte(strings.Repeat(" ", width))
+	for _, band := range cnnfag.Bands {
+		from, to := col(band.Min), col(band.Max)
+		name := band.Rating
+		if len(name) > to-from-1 {
+			name = bandAbbrev[name]
+		}
+		if len(name) > to-from-1 {
+			continue
+		}
+		copy(names[from+(to-from-len(name))/2+1:], name)
+	}
+	b.WriteString(strings.TrimRight(string(names), " ") + "\n")
+
+	fmt.Fprintf(&b, "c previous close %.0f %s w week ago %.0f %s m month ago %.0f\n",
+		res.PreviousClose, g.sep, res.OneWeekAgo, g.sep, res.OneMonthAgo)
+	return b.String()
+}
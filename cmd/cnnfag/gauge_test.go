@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestGauge(t *testing.T) {
+	res := cnnfag.Result{Score: 28, Rating: "fear", PreviousClose: 32, OneWeekAgo: 32, OneMonthAgo: 51}
+
+	got := gauge(res, 60, false, false)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5:\n%s", len(lines), got)
+	}
+	// The needle sits over column 17, 28% of the way along 60 columns.
+	if want := strings.Repeat(" ", 17) + "▼ 28 fear"; lines[0] != want {
+		t.Errorf("needle line = %q, want %q", lines[0], want)
+	}
+	if n := strings.Count(lines[1], "┼"); n != 4 {
+		t.Errorf("scale has %d band boundaries, want 4: %q", n, lines[1])
+	}
+	// The previous close hides the week-ago marker in the same column.
+	if strings.TrimSpace(lines[2]) != "c          m" {
+		t.Errorf("marker line = %q", lines[2])
+	}
+	for _, want := range []string{"extreme fear", "neut", "extreme greed"} {
+		if !strings.Contains(lines[3], want) {
+			t.Errorf("band names %q lack %q", lines[3], want)
+		}
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Error("uncolored gauge contains escape codes")
+	}
+
+	// Near the right edge the label goes left of the needle.
+	res.Score, res.Rating = 97, "extreme greed"
+	if line := strings.SplitN(gauge(res, 30, false, true), "\n", 2)[0]; !strings.HasSuffix(line, "97 extreme greed v") {
+		t.Errorf("ascii needle line = %q", line)
+	}
+	ascii := gauge(res, 30, false, true)
+	for _, r := range ascii {
+		if r > 127 {
+			t.Fatalf("ascii gauge contains %q:\n%s", r, ascii)
+		}
+	}
+
+	colored := gauge(res, 60, true, false)
+	for rating, code := range ratingColors {
+		if !strings.Contains(colored, code) {
+			t.Errorf("colored gauge lacks the %s color", rating)
+		}
+	}
+}
+
+func TestRunGauge(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	var stdout, stderr strings.Builder
+	if code := run([]string{"gauge", "-width", "40"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(gauge) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "▼ 64 greed") {
+		t.Errorf("gauge output:\n%s", stdout.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"gauge", "-width", "10"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("run(gauge -width 10) = %d, want 2", code)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// runHistory prints the daily history of the index or of one indicator,
+// filtered by date, in a format spreadsheets and pandas read directly.
+func runHistory(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag history", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	since := fs.String("since", "", "first `date` to include (2006-01-02)")
+	until := fs.String("until", "", "last `date` to include (2006-01-02)")
+	format := fs.String("format", "table", "output format: csv, tsv, ndjson or table")
+	indicator := fs.String("indicator", "index", "series to print: index or one of "+strings.Join(cnnfag.IndicatorIDs, ", "))
+	columns := fs.String("columns", "", "comma-separated columns: date, score or value, rating (default date,score,rating for the index, date,value,rating for an indicator)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	from, to, err := dateRange(*since, *until)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag history:", err)
+		return 2
+	}
+	if !validSeries(*indicator) {
+		fmt.Fprintf(stderr, "cnnfag history: unknown indicator %q\n", *indicator)
+		return 2
+	}
+	if *columns == "" {
+		*columns = "date,value,rating"
+		if *indicator == "index" {
+			*columns = "date,score,rating"
+		}
+	}
+	cols := strings.Split(*columns, ",")
+	for i, c := range cols {
+		c = strings.TrimSpace(c)
+		cols[i] = c
+		if c != "date" && c != "value" && c != "score" && c != "rating" {
+			fmt.Fprintf(stderr, "cnnfag history: unknown column %q\n", c)
+			return 2
+		}
+	}
+	write, ok := historyWriters[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "cnnfag history: unknown format %q\n", *format)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag history:", err)
+		return 1
+	}
+	series, _ := res.Series(*indicator)
+	var rows []cnnfag.Value
+	for _, v := range series {
+		if (from.IsZero() || !v.Date.Before(from)) && (to.IsZero() || v.Date.Before(to)) {
+			rows = append(rows, v)
+		}
+	}
+
+	if err := write(stdout, cols, rows); err != nil {
+		fmt.Fprintln(stderr, "cnnfag history:", err)
+		return 1
+	}
+	return 0
+}
+
+// validSeries reports whether id names a series Result.Series knows.
+func validSeries(id string) bool {
+	_, ok := cnnfag.Result{}.Series(id)
+	return ok
+}
+
+// dateRange parses -since and -until into a half-open range: until's whole
+// day is included. Empty flags leave that end open; a since after until is
+// an error rather than a silently empty range.
+func dateRange(since, until string) (from, to time.Time, err error) {
+	if since != "" {
+		if from, err = time.Parse(time.DateOnly, since); err != nil {
+			return from, to, fmt.Errorf("-since %q is not a 2006-01-02 date", since)
+		}
+	}
+	if until != "" {
+		if to, err = time.Parse(time.DateOnly, until); err != nil {
+			return from, to, fmt.Errorf("-until %q is not a 2006-01-02 date", until)
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("-since %s is after -until %s", since, until)
+	}
+	return from, to, nil
+}
+
+// field formats one column of a row as text.
+func field(col string, v cnnfag.Value) string {
+	switch col {
+	case "date":
+		return v.Date.Format(time.DateOnly)
+	case "rating":
+		return v.Rating
+	default:
+		return strconv.FormatFloat(v.Value, 'f', -1, 64)
+	}
+}
+
+var historyWriters = map[string]func(io.Writer, []string, []cnnfag.Value) error{
+	"csv": func(w io.Writer, cols []string, rows []cnnfag.Value) error { return writeDelimited(w, ',', cols, rows) },
+	"tsv": func(w io.Writer, cols []string, rows []cnnfag.Value) error {
+		return writeDelimited(w, '\t', cols, rows)
+	},
+	"ndjson": writeNDJSON,
+	"table":  writeTable,
+}
+
+func writeDelimited(w io.Writer, comma rune, cols []string, rows []cnnfag.Value) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+	record := make([]string, len(cols))
+	for _, v := range rows {
+		for i, c := range cols {
+			record[i] = field(c, v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeNDJSON writes one object per row with keys in column order, which a
+// map would not keep.
+func writeNDJSON(w io.Writer, cols []string, rows []cnnfag.Value) error {
+	var b strings.Builder
+	for _, v := range rows {
+		b.Reset()
+		b.WriteByte('{')
+		for i, c := range cols {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(c)
+			b.Write(key)
+			b.WriteByte(':')
+			if c == "value" || c == "score" {
+				b.WriteString(field(c, v))
+			} else {
+				val, _ := json.Marshal(field(c, v))
+				b.Write(val)
+			}
+		}
+		b.WriteString("}\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, cols []string, rows []cnnfag.Value) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(cols, "\t"))
+	fields := make([]string, len(cols))
+	for _, v := range rows {
+		for i, c := range cols {
+			fields[i] = field(c, v)
+			if c == "value" || c == "score" {
+				fields[i] = strconv.FormatFloat(v.Value, 'f', 2, 64)
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(fields, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunHistory(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"-format", "csv"},
+			"date,score,rating\n2025-08-11,57.628571428571426,greed\n2025-08-12,62.25714285714286,greed\n2025-08-13,63.34285714285714,greed\n",
+		},
+		{
+			[]string{"-format", "tsv", "-since", "2025-08-12", "-until", "2025-08-12", "-columns", "date, score"},
+			"date\tscore\n2025-08-12\t62.25714285714286\n",
+		},
+		{
+			[]string{"-format", "ndjson", "-indicator", "vix", "-since", "2025-08-13"},
+			`{"date":"2025-08-13","value":14.49,"rating":"extreme fear"}` + "\n",
+		},
+		{
+			[]string{"-indicator", "momentum", "-until", "2025-08-11"},
+			"date        value    rating\n2025-08-11  6373.45  extreme greed\n",
+		},
+	} {
+		var stdout, stderr strings.Builder
+		args := append([]string{"history"}, tc.args...)
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("run(%q) = %d, stderr: %s", args, code, stderr.String())
+		}
+		if stdout.String() != tc.want {
+			t.Errorf("run(%q) output:\n%s\nwant:\n%s", args, stdout.String(), tc.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"history", "-format", "xml"},
+		{"history", "-indicator", "gold"},
+		{"history", "-columns", "date,volume"},
+		{"history", "-since", "last week"},
+		{"history", "-since", "2025-08-13", "-until", "2025-08-12"},
+	} {
+		var stdout, stderr strings.Builder
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("run(%q) printed no error", args)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// What each indicator's raw series measures, by cnnfag.IndicatorIDs.
+var indicatorInfo = map[string]struct{ name, unit string }{
+	"momentum":   {"Market momentum", "S&P 500"},
+	"strength":   {"Stock price strength", "% net new highs"},
+	"breadth":    {"Stock price breadth", "McClellan volume"},
+	"put_call":   {"Put and call options", "put/call ratio"},
+	"vix":        {"Market volatility", "VIX"},
+	"junk_bond":  {"Junk bond demand", "% yield spread"},
+	"safe_haven": {"Safe haven demand", "% stocks - bonds"},
+}
+
+// runIndicators prints the seven components as a table: score and rating,
+// the latest raw value with its unit and its change since the previous
+// close, and when CNN last updated the indicator.
+func runIndicators(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag indicators", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sortBy := fs.String("sort", "", "row order: score (highest first) or name; CNN's order by default")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *sortBy != "" && *sortBy != "score" && *sortBy != "name" {
+		fmt.Fprintf(stderr, "cnnfag indicators: unknown sort %q\n", *sortBy)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag indicators:", err)
+		return 1
+	}
+
+	ids := append([]string(nil), cnnfag.IndicatorIDs...)
+	switch *sortBy {
+	case "score":
+		sort.SliceStable(ids, func(i, j int) bool {
+			a, _ := res.Indicator(ids[i])
+			b, _ := res.Indicator(ids[j])
+			return a.Score > b.Score
+		})
+	case "name":
+		sort.Slice(ids, func(i, j int) bool { return indicatorInfo[ids[i]].name < indicatorInfo[ids[j]].name })
+	}
+
+	rows := make([][]string, 0, len(ids))
+	ratings := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ind, _ := res.Indicator(id)
+		latest, change := "", ""
+		if n := len(ind.History); n > 0 {
+			latest = formatRaw(ind.History[n-1].Value)
+			if n > 1 {
+				change = formatChange(ind.History[n-1].Value - ind.History[n-2].Value)
+			}
+		}
+		rows = append(rows, []string{
+			indicatorInfo[id].name,
+			strconv.FormatFloat(ind.Score, 'f', 0, 64),
+			ind.Rating,
+			latest,
+			indicatorInfo[id].unit,
+			change,
+			ind.Timestamp.Format(time.RFC3339),
+		})
+		ratings = append(ratings, ind.Rating)
+	}
+
+	color := useColor(stdout)
+	writeAligned(stdout, []string{"INDICATOR", "SCORE", "RATING", "LATEST", "UNIT", "CHANGE", "UPDATED"}, rows,
+		func(row, col int, cell string) string {
+			if color && col == 2 {
+				return colorize(ratings[row], cell)
+			}
+			return cell
+		})
+	return 0
+}
+
+// formatRaw prints a raw value with a precision that suits its size: an S&P
+// level needs no decimals, a put/call ratio needs a few.
+func formatRaw(v float64) string {
+	switch a := math.Abs(v); {
+	case a >= 1000:
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	case a >= 10:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	default:
+		return strconv.FormatFloat(v, 'f', 3, 64)
+	}
+}
+
+func formatChange(d float64) string {
+	s := formatRaw(d)
+	if d >= 0 {
+		s = "+" + s
+	}
+	return s
+}
+
+// writeAligned prints a table with columns padded to their widest cell.
+// style may decorate a padded cell, for example with ANSI color, which
+// text/tabwriter would count towards the width.
+func writeAligned(w io.Writer, header []string, rows [][]string, style func(row, col int, cell string) string) {
+	widths := make([]int, len(header))
+	for _, r := range append([][]string{header}, rows...) {
+		for i, cell := range r {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	line := func(r []string, decorate func(col int, cell string) string) {
+		var b strings.Builder
+		for i, cell := range r {
+			if i < len(r)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			}
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(decorate(i, cell))
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+
+	line(header, func(_ int, cell string) string { return cell })
+	for i, r := range rows {
+		line(r, func(col int, cell string) string { return style(i, col, cell) })
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunIndicators(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	var stdout, stderr strings.Builder
+	if code := run([]string{"indicators"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(indicators) = %d, stderr: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("got %d lines, want a header and 7 rows:\n%s", len(lines), stdout.String())
+	}
+	if !strings.HasPrefix(lines[0], "INDICATOR") || !strings.HasPrefix(lines[1], "Market momentum") {
+		t.Errorf("rows are not in CNN's order:\n%s", stdout.String())
+	}
+	// VIX went from 14.73 to 14.49 on the fixture's last two days.
+	vix := lines[5]
+	for _, want := range []string{"Market volatility", "50", "neutral", "14.49", "VIX", "-0.240", "2026-08-10T20:15:01Z"} {
+		if !strings.Contains(vix, want) {
+			t.Errorf("volatility row lacks %q: %q", want, vix)
+		}
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Error("output to a non-terminal is colored")
+	}
+
+	stdout.Reset()
+	if code := run([]string{"indicators", "-sort", "score"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(indicators -sort score) = %d, stderr: %s", code, stderr.String())
+	}
+	lines = strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if !strings.HasPrefix(lines[1], "Junk bond demand") || !strings.HasPrefix(lines[7], "Stock price strength") {
+		t.Errorf("rows are not sorted by score:\n%s", stdout.String())
+	}
+
+	if code := run([]string{"indicators", "-sort", "color"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("run(indicators -sort color) = %d, want 2", code)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	if got := colorize("greed", "64"); got != "\x1b[32m64\x1b[0m" {
+		t.Errorf("colorize(greed) = %q", got)
+	}
+	if got := colorize("", "64"); got != "64" {
+		t.Errorf("colorize without a rating = %q", got)
+	}
+	t.Setenv("NO_COLOR", "1")
+	if useColor(os.Stdout) {
+		t.Error("useColor ignores NO_COLOR")
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefresh is how long keys fetched from a URL are used before they
+	// are fetched again; an unknown key ID fetches them sooner, but at most
+	// once per jwksRetry, so junk tokens cannot hammer the issuer.
+	jwksRefresh = time.Hour
+	jwksRetry   = time.Minute
+
+	// jwtLeeway allows for clock skew between us and the issuer.
+	jwtLeeway = time.Minute
+)
+
+// keySet holds the public keys that sign access tokens, read from a JWKS
+// file once or from a URL as needed.
+type keySet struct {
+	url    string // empty for a file
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey // by key ID
+	fetched time.Time                   // zero until the URL was fetched
+	tried   time.Time
+}
+
+// loadKeySet reads a JWKS from src, a file or an http(s) URL. Keys from a
+// URL are fetched on first use, so the issuer need not be up when we start.
+func loadKeySet(src string) (*keySet, error) {
+	if strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://") {
+		return &keySet{url: src, client: &http.Client{Timeout: 10 * time.Second}}, nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	return &keySet{keys: keys}, nil
+}
+
+// key returns the key with the given ID. The fetch it may make happens
+// outside the lock, so a slow issuer holds up only the request that
+// triggered it; the others go on with the keys at hand.
+func (k *keySet) key(kid string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	key, ok := k.keys[kid]
+	t := now()
+	if k.url == "" || (ok && t.Sub(k.fetched) <= jwksRefresh) || t.Sub(k.tried) < jwksRetry {
+		k.mu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		return key, nil
+	}
+	k.tried = t
+	k.mu.Unlock()
+
+	keys, err := k.fetch()
+	if err != nil {
+		if ok {
+			return key, nil // keep using the old keys while the issuer is down
+		}
+		return nil, fmt.Errorf("fetching keys: %w", err)
+	}
+	k.mu.Lock()
+	k.keys, k.fetched = keys, t
+	k.mu.Unlock()
+	if key, ok = keys[kid]; !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return key, nil
+}
+
+func (k *keySet) fetch() (map[string]crypto.PublicKey, error) {
+	resp, err := k.client.Get(k.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", k.url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", k.url, err)
+	}
+	return keys, nil
+}
+
+// parseJWKS returns the RSA and P-256 signing keys of a JSON Web Key Set by
+// key ID, skipping keys of other types and keys for encryption.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty, Kid, Use, Crv string
+			N, E, X, Y         string
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.Kty == "RSA":
+			n, err1 := base64.RawURLEncoding.DecodeString(k.N)
+			e, err2 := base64.RawURLEncoding.DecodeString(k.E)
+			if err := errors.Join(err1, err2); err != nil || len(e) > 4 {
+				return nil, fmt.Errorf("key %q: bad RSA key", k.Kid)
+			}
+			keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case k.Kty == "EC" && k.Crv == "P-256":
+			x, err1 := base64.RawURLEncoding.DecodeString(k.X)
+			y, err2 := base64.RawURLEncoding.DecodeString(k.Y)
+			if err := errors.Join(err1, err2); err != nil || len(x) != 32 || len(y) != 32 {
+				return nil, fmt.Errorf("key %q: bad EC key", k.Kid)
+			}
+			// crypto/ecdh checks the point is on the curve.
+			if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+				return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA or P-256 signing keys")
+	}
+	return keys, nil
+}
+
+// tokenClaims are the claims of an access token we check.
+type tokenClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	Expires   float64  `json:"exp"`
+	NotBefore float64  `json:"nbf"`
+}
+
+// audience is the aud claim, a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+		err := json.Unmarshal(data, &s)
+		*a = audience{s}
+		return err
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// verifyJWT checks the signature of a JWT access token signed with RS256 or
+// ES256 and returns its claims. Checking them is up to the caller.
+func verifyJWT(token string, keys *keySet) (tokenClaims, error) {
+	var claims tokenClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("malformed token")
+	}
+	var header struct{ Alg, Kid string }
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return claims, fmt.Errorf("malformed token header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, errors.New("malformed token signature")
+	}
+	key, err := keys.key(header.Kid)
+	if err != nil {
+		return claims, err
+	}
+
+	// The key decides the algorithm, and the header has to agree, so a
+	// token cannot pick a weaker one.
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != "RS256" {
+			return claims, fmt.Errorf("algorithm %q does not match key %q", header.Alg, header.Kid)
+		}
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig)
+	case *ecdsa.PublicKey:
+		if header.Alg != "ES256" {
+			return claims, fmt.Errorf("algorithm %q does not match key %q", header.Alg, header.Kid)
+		}
+		if len(sig) != 64 || !ecdsa.Verify(key, hash[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			err = errors.New("bad signature")
+		}
+	}
+	if err != nil {
+		return claims, errors.New("invalid signature")
+	}
+
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return claims, fmt.Errorf("malformed token claims: %w", err)
+	}
+	return claims, nil
+}
+
+func decodeSegment(s string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
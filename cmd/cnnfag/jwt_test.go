@@ -0,0 +1,270 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testIssuer is a local authorization server: it signs tokens and serves
+// its keys as a JWKS.
+type testIssuer struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]crypto.Signer
+	fetches int
+	hold    chan struct{} // if set, JWKS requests wait until it is closed
+	held    int
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	iss := &testIssuer{t: t, keys: map[string]crypto.Signer{}}
+	iss.addKey("rsa1", "RS256")
+	iss.addKey("ec1", "ES256")
+	iss.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		iss.mu.Lock()
+		hold := iss.hold
+		if hold != nil {
+			iss.held++
+		}
+		iss.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+		iss.mu.Lock()
+		defer iss.mu.Unlock()
+		iss.fetches++
+		w.Write(iss.jwks())
+	}))
+	t.Cleanup(iss.srv.Close)
+	return iss
+}
+
+func (iss *testIssuer) addKey(kid, alg string) {
+	var key crypto.Signer
+	var err error
+	if alg == "RS256" {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	} else {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		iss.t.Fatal(err)
+	}
+	iss.mu.Lock()
+	iss.keys[kid] = key
+	iss.mu.Unlock()
+}
+
+// waiting reports how many JWKS requests are waiting on hold.
+func (iss *testIssuer) waiting() int {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	return iss.held
+}
+
+func (iss *testIssuer) fetched() int {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	return iss.fetches
+}
+
+func (iss *testIssuer) jwks() []byte {
+	b64 := base64.RawURLEncoding.EncodeToString
+	var keys []map[string]string
+	for kid, key := range iss.keys {
+		switch pub := key.Public().(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes())})
+		case *ecdsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": b64(pub.X.FillBytes(make([]byte, 32))), "y": b64(pub.Y.FillBytes(make([]byte, 32)))})
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"keys": keys})
+	return data
+}
+
+// sign returns a token with the given claims signed by key kid, using alg
+// in the header.
+func (iss *testIssuer) sign(kid, alg string, claims map[string]any) string {
+	iss.t.Helper()
+	b64 := base64.RawURLEncoding.EncodeToString
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "at+jwt"})
+	body, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(body)
+	hash := sha256.Sum256([]byte(input))
+
+	iss.mu.Lock()
+	key := iss.keys[kid]
+	iss.mu.Unlock()
+	var sig []byte
+	var err error
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key, hash[:])
+		if err == nil {
+			sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	}
+	if err != nil {
+		iss.t.Fatal(err)
+	}
+	return input + "." + b64(sig)
+}
+
+func TestVerifyJWT(t *testing.T) {
+	defer func() { now = time.Now }()
+	t0 := time.Date(2026, 8, 11, 14, 0, 0, 0, time.UTC)
+	now = func() time.Time { return t0 }
+
+	iss := newTestIssuer(t)
+	keys, err := loadKeySet(iss.srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := map[string]any{"iss": "https://issuer.example", "sub": "alice", "aud": []string{"https://mcp.example/mcp"}, "exp": t0.Add(time.Hour).Unix()}
+
+	for _, kid := range []string{"rsa1", "ec1"} {
+		alg := map[string]string{"rsa1": "RS256", "ec1": "ES256"}[kid]
+		got, err := verifyJWT(iss.sign(kid, alg, claims), keys)
+		if err != nil || got.Subject != "alice" || !got.Audience.has("https://mcp.example/mcp/") {
+			t.Errorf("%s: %+v, %v", alg, got, err)
+		}
+	}
+	if iss.fetched() != 1 {
+		t.Errorf("fetched the keys %d times, want 1", iss.fetched())
+	}
+
+	good := iss.sign("rsa1", "RS256", claims)
+	parts := strings.Split(good, ".")
+	other, _ := json.Marshal(map[string]any{"sub": "mallory", "aud": "https://mcp.example/mcp", "exp": t0.Add(time.Hour).Unix()})
+	for name, token := range map[string]string{
+		"tampered":        parts[0] + "." + base64.RawURLEncoding.EncodeToString(other) + "." + parts[2],
+		"wrong algorithm": iss.sign("rsa1", "ES256", claims),
+		"none":            base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa1"}`)) + "." + parts[1] + ".",
+		"two parts":       parts[0] + "." + parts[1],
+		"garbage":         "not.a.token",
+		"unknown key":     strings.Replace(good, parts[0], base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"nope"}`)), 1),
+	} {
+		if _, err := verifyJWT(token, keys); err == nil {
+			t.Errorf("%s: verified", name)
+		}
+	}
+	// Unknown key IDs do not refetch more than once a minute.
+	if iss.fetched() != 1 {
+		t.Errorf("junk tokens fetched the keys %d times", iss.fetched()-1)
+	}
+
+	// A rotated-in key is picked up once the retry interval has passed.
+	iss.addKey("rsa2", "RS256")
+	rotated := iss.sign("rsa2", "RS256", claims)
+	if _, err := verifyJWT(rotated, keys); err == nil {
+		t.Error("new key used before the retry interval")
+	}
+	now = func() time.Time { return t0.Add(2 * jwksRetry) }
+	if _, err := verifyJWT(rotated, keys); err != nil {
+		t.Errorf("rotated key: %v", err)
+	}
+
+	// Keys from a file.
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, iss.jwks(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fileKeys, err := loadKeySet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyJWT(iss.sign("ec1", "ES256", claims), fileKeys); err != nil {
+		t.Errorf("key from file: %v", err)
+	}
+}
+
+func TestVerifyJWTSlowIssuer(t *testing.T) {
+	var offset atomic.Int64
+	now = func() time.Time { return time.Now().Add(time.Duration(offset.Load())) }
+	defer func() { now = time.Now }()
+
+	iss := newTestIssuer(t)
+	keys, err := loadKeySet(iss.srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := map[string]any{"sub": "alice", "exp": time.Now().Add(3 * time.Hour).Unix()}
+	token := iss.sign("rsa1", "RS256", claims)
+	if _, err := verifyJWT(token, keys); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the keys are stale, one request refetches them from an issuer
+	// that hangs; the others carry on with the keys they have.
+	hold := make(chan struct{})
+	release := sync.OnceFunc(func() { close(hold) })
+	defer release()
+	iss.mu.Lock()
+	iss.hold = hold
+	iss.mu.Unlock()
+	offset.Store(int64(jwksRefresh + time.Minute))
+	refetched := make(chan error)
+	go func() {
+		_, err := verifyJWT(token, keys)
+		refetched <- err
+	}()
+	for iss.waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := verifyJWT(iss.sign("ec1", "ES256", claims), keys)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("verify during the refetch: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("verify waited for the refetch")
+	}
+
+	release()
+	if err := <-refetched; err != nil {
+		t.Errorf("verify that refetched: %v", err)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	for _, bad := range []string{
+		`not json`,
+		`{"keys":[]}`,
+		`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`,
+		`{"keys":[{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","x":"AQAB","y":"AQAB"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","x":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA","y":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}]}`,
+	} {
+		if _, err := parseJWKS([]byte(bad)); err == nil {
+			t.Errorf("parseJWKS(%s): no error", bad)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Most MCP clients do not show a server's stderr, so what happens inside a
+// fetch goes to the client instead: source fallbacks, signs that CNN changed
+// its API and cache hits as notifications/message at the level the client
+// sets, and the steps of a fetch as notifications/progress for requests
+// that carry a progress token.
+
+// mcpCacheTTL is how long a session reuses a fetched result. CNN updates
+// the index every few minutes at most, and a model often calls several
+// tools in a row.
+const mcpCacheTTL = time.Minute
+
+// logLevels are the syslog severities MCP logging uses, least severe first.
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// defaultLogLevel applies until the client sends logging/setLevel.
+const defaultLogLevel = "info"
+
+func levelRank(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *mcpSession) setLevel(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || levelRank(p.Level) < 0 {
+		return nil, &rpcError{-32602, fmt.Sprintf("level must be one of %q", logLevels)}
+	}
+	s.mu.Lock()
+	s.logLevel = p.Level
+	s.mu.Unlock()
+	return struct{}{}, nil
+}
+
+// log sends a message to the client if level is at or above the session's,
+// with the messages of the request ctx belongs to if any.
+func (s *mcpSession) log(ctx context.Context, level, message string) {
+	s.mu.Lock()
+	min := s.logLevel
+	s.mu.Unlock()
+	if min == "" {
+		min = defaultLogLevel
+	}
+	if levelRank(level) < levelRank(min) {
+		return
+	}
+	sink := replyFrom(ctx)
+	if sink == nil {
+		sink = s.out
+	}
+	sink.send(rpcNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/message",
+		Params:  map[string]any{"level": level, "logger": "cnnfag", "data": message},
+	})
+}
+
+type progressKey struct{}
+
+// progress reports the steps of one request to a client that asked for them
+// with _meta.progressToken. The total is not known in advance: how many
+// sources a fetch tries depends on which answer.
+type progress struct {
+	out   messageSink
+	token json.RawMessage
+
+	mu sync.Mutex
+	n  int
+}
+
+// withProgress attaches a progress reporter to ctx if params carry a token.
+func withProgress(ctx context.Context, out messageSink, params json.RawMessage) context.Context {
+	var p struct {
+		Meta struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	json.Unmarshal(params, &p) // no token on any error
+	if len(p.Meta.ProgressToken) == 0 || string(p.Meta.ProgressToken) == "null" {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progress{out: out, token: p.Meta.ProgressToken})
+}
+
+// step reports the next step. It does nothing on a nil progress, so callers
+// need not check whether the client asked.
+func (p *progress) step(message string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.n++
+	n := p.n
+	p.mu.Unlock()
+	p.out.send(rpcNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/progress",
+		Params:  map[string]any{"progressToken": p.token, "progress": n, "message": message},
+	})
+}
+
+func progressFrom(ctx context.Context) *progress {
+	p, _ := ctx.Value(progressKey{}).(*progress)
+	return p
+}
+
+// observe returns the session's fetch: a lookup in cache, reporting what
+// happens inside it as progress and log messages.
+func (s *mcpSession) observe(cache *resultCache) func(context.Context) (cnnfag.Result, error) {
+	return func(ctx context.Context) (cnnfag.Result, error) {
+		p := progressFrom(ctx)
+		trace := &cnnfag.Trace{
+			SourceStart: func(name string) { p.step("fetching from " + name) },
+			SourceDone: func(name string, err error) {
+				switch {
+				case err == nil || ctx.Err() != nil:
+				case errors.Is(err, cnnfag.ErrEmptyResult):
+					s.log(ctx, "warning", name+" answered without the index; CNN may have changed its API: "+err.Error())
+				default:
+					s.log(ctx, "warning", name+" failed: "+err.Error())
+				}
+			},
+		}
+
+		res, cached, err := cache.lookup(ctx, trace)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			s.log(ctx, "error", "fetching the index: "+err.Error())
+		case err != nil:
+			// Cancelled or timed out; the request's own response says so.
+		case cached:
+			s.log(ctx, "debug", "served from the cache")
+			p.step("served from the cache")
+		case res.Source == "page":
+			s.log(ctx, "warning", "only the public page answered, so indicators and history are missing; CNN may have changed its API")
+		}
+		return res, err
+	}
+}
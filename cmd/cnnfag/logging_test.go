@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// refusingTransport serves the fixture to Firefox and 418 to everyone else.
+type refusingTransport struct{ body []byte }
+
+func (t refusingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !strings.Contains(r.Header.Get("User-Agent"), "Firefox") {
+		return &http.Response{StatusCode: http.StatusTeapot, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(t.body)), Header: make(http.Header)}, nil
+}
+
+func TestLoggingAndProgress(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: refusingTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+	clock := time.Date(2026, 8, 13, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := serveMCP(inR, outW, cnnfag.Get)
+		outW.Close()
+		done <- err
+	}()
+	out := bufio.NewScanner(outR)
+
+	// call sends a request and returns what the server writes up to and
+	// including its response: requests here run one at a time.
+	id := 0
+	call := func(method, params string) []string {
+		t.Helper()
+		id++
+		fmt.Fprintf(inW, `{"jsonrpc":"2.0","id":%d,"method":%q,"params":%s}`+"\n", id, method, params)
+		var lines []string
+		for out.Scan() {
+			lines = append(lines, out.Text())
+			if strings.HasPrefix(out.Text(), fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,`, id)) {
+				return lines
+			}
+		}
+		t.Fatalf("no response to %s", method)
+		return nil
+	}
+
+	if got := call("initialize", `{"protocolVersion":"2025-06-18"}`); !strings.Contains(got[0], `"logging":{}`) {
+		t.Errorf("initialize: %s", got)
+	}
+	if got := call("logging/setLevel", `{"level":"debug"}`); len(got) != 1 || !strings.Contains(got[0], `"result":{}`) {
+		t.Errorf("setLevel: %s", got)
+	}
+
+	// A fetch reports each source it tries as progress, and the one that
+	// was refused as a warning.
+	got := call("tools/call", `{"name":"get_fear_and_greed","_meta":{"progressToken":"tok"}}`)
+	want := []string{
+		`{"jsonrpc":"2.0","method":"notifications/progress","params":{"message":"fetching from graphdata","progress":1,"progressToken":"tok"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"graphdata failed: unexpected http status: 418","level":"warning","logger":"cnnfag"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/progress","params":{"message":"fetching from graphdata-firefox","progress":2,"progressToken":"tok"}}`,
+	}
+	if len(got) != 4 || strings.Join(got[:3], "\n") != strings.Join(want, "\n") || !strings.Contains(got[3], "64.37") {
+		t.Errorf("first call wrote:\n%s", strings.Join(got, "\n"))
+	}
+
+	// Within a minute the next call is served from the cache, which is
+	// debug news; without a progress token there is no progress.
+	got = call("tools/call", `{"name":"get_statistics","arguments":{"series":"index"}}`)
+	if len(got) != 2 || got[0] != `{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"served from the cache","level":"debug","logger":"cnnfag"}}` {
+		t.Errorf("cached call wrote:\n%s", strings.Join(got, "\n"))
+	}
+
+	// An answer without the index, as after an API change, is called out.
+	clock = clock.Add(2 * time.Minute)
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{[]byte("{}")}}
+	got = call("tools/call", `{"name":"get_fear_and_greed"}`)
+	if len(got) != 3 || !strings.Contains(got[0], `"data":"graphdata answered without the index; CNN may have changed its API`) ||
+		!strings.Contains(got[1], `"level":"error"`) || !strings.Contains(got[2], `"isError":true`) {
+		t.Errorf("drifted call wrote:\n%s", strings.Join(got, "\n"))
+	}
+
+	// At level error, warnings are dropped and failures still reported.
+	if got := call("logging/setLevel", `{"level":"loud"}`); !strings.Contains(got[0], `"code":-32602`) {
+		t.Errorf("setLevel loud: %s", got)
+	}
+	call("logging/setLevel", `{"level":"error"}`)
+	clock = clock.Add(2 * time.Minute)
+	cnnfag.HTTPClient = &http.Client{Transport: errorTransport{}}
+	got = call("tools/call", `{"name":"get_fear_and_greed"}`)
+	if len(got) != 2 || !strings.Contains(got[0], `"level":"error"`) || !strings.Contains(got[0], "fetching the index: graphdata:") ||
+		!strings.Contains(got[1], `"isError":true`) {
+		t.Errorf("failed call wrote:\n%s", strings.Join(got, "\n"))
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
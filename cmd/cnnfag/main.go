@@ -1,19 +1,37 @@
 // Command cnnfag prints CNN's Fear & Greed index as text or JSON, and can run
-// a Model Context Protocol server exposing the index as a tool ("cnnfag mcp").
+// a Model Context Protocol server exposing the index as a tool ("cnnfag mcp"),
+// over stdio or, with -http, over the Streamable HTTP transport.
+// "cnnfag history" prints the daily history of the index or an indicator as
+// CSV, TSV, NDJSON or a table, and "cnnfag indicators" breaks the index down
+// into its seven components. "cnnfag chart" draws the history in the
+// terminal and "cnnfag gauge" the current score; "cnnfag render" and
+// "cnnfag svg" draw them as PNG and SVG images, and "cnnfag serve" serves
+// the images and a status badge over HTTP. "cnnfag animate" makes a GIF of
+// the gauge moving through the history, and "cnnfag watch" keeps a live
+// dashboard on screen. "cnnfag check" tests thresholds for scripts and
+// Nagios-style monitoring. "cnnfag record" saves raw API responses as test
+// fixtures, and -replay answers any command from those recordings instead
+// of CNN.
 package main
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"text/template"
 	"time"
 
 	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
 )
 
+// commands lists the subcommands for the unknown-command message.
+var commands = []string{"animate", "chart", "check", "gauge", "history", "indicators", "mcp", "record", "render", "serve", "svg", "watch"}
+
 func main() {
 	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
@@ -23,26 +41,91 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 	fs.SetOutput(stderr)
 	jsonOut := fs.Bool("json", false, "print the full result, including history, as JSON")
 	timeout := fs.Duration("timeout", 15*time.Second, "request timeout")
+	replay := fs.String("replay", "", "answer from the recordings in `dir` instead of CNN")
+	asOf := fs.String("as-of", "", "with -replay, use the latest recording made by this `date` (2006-01-02 or RFC 3339)")
+	format := fs.String("format", "", "print the result through this Go `template` instead")
+	templateFile := fs.String("template-file", "", "like -format, with the template read from `path`")
+	provider := fs.String("provider", "cnn", "sentiment index to print: "+strings.Join(cnnfag.ProviderNames(), ", "))
 	if err := fs.Parse(args); err != nil {
 		return 2
 	}
 
+	if fs.Arg(0) != "" && *provider != "cnn" {
+		fmt.Fprintf(stderr, "cnnfag: -provider works only without a command; %s reads CNN's index\n", fs.Arg(0))
+		return 2
+	}
+	// The output flags shape the plain print; a command has its own, given
+	// after its name.
+	var output string
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "json", "format", "template-file":
+			output = f.Name
+		}
+	})
+	if fs.Arg(0) != "" && output != "" {
+		fmt.Fprintf(stderr, "cnnfag: -%s works only without a command; put %s's own flags after its name\n", output, fs.Arg(0))
+		return 2
+	}
+
+	tmpl, err := parseTemplate(*format, *templateFile)
+	if err == nil && tmpl != nil && *jsonOut {
+		err = errors.New("-json and a template are mutually exclusive")
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag:", err)
+		return 2
+	}
+
+	if *replay != "" || *asOf != "" {
+		client, err := replayClient(*replay, *asOf)
+		if err != nil {
+			fmt.Fprintln(stderr, "cnnfag:", err)
+			return 2
+		}
+		old := cnnfag.HTTPClient
+		cnnfag.HTTPClient = client
+		defer func() { cnnfag.HTTPClient = old }()
+	}
+
 	switch fs.Arg(0) {
 	case "":
 	case "mcp":
-		if err := serveMCP(stdin, stdout, cnnfag.Get); err != nil {
-			fmt.Fprintln(stderr, "cnnfag mcp:", err)
-			return 1
-		}
-		return 0
+		return runMCP(fs.Args()[1:], stdin, stdout, stderr)
+	case "record":
+		return runRecord(fs.Args()[1:], *timeout, stdout, stderr)
+	case "history":
+		return runHistory(fs.Args()[1:], *timeout, stdout, stderr)
+	case "indicators":
+		return runIndicators(fs.Args()[1:], *timeout, stdout, stderr)
+	case "chart":
+		return runChart(fs.Args()[1:], *timeout, stdout, stderr)
+	case "gauge":
+		return runGauge(fs.Args()[1:], *timeout, stdout, stderr)
+	case "render":
+		return runRender(fs.Args()[1:], *timeout, stdout, stderr)
+	case "svg":
+		return runSVG(fs.Args()[1:], *timeout, stdout, stderr)
+	case "serve":
+		return runServe(fs.Args()[1:], *timeout, stdout, stderr)
+	case "animate":
+		return runAnimate(fs.Args()[1:], *timeout, stdout, stderr)
+	case "watch":
+		return runWatch(fs.Args()[1:], *timeout, stdout, stderr)
+	case "check":
+		return runCheck(fs.Args()[1:], *timeout, stdout, stderr)
 	default:
-		fmt.Fprintf(stderr, "cnnfag: unknown command %q, the only command is \"mcp\"\n", fs.Arg(0))
+		fmt.Fprintf(stderr, "cnnfag: unknown command %q, the commands are %s\n", fs.Arg(0), strings.Join(commands, ", "))
 		return 2
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
 
+	if *provider != "cnn" {
+		return printSnapshot(ctx, *provider, *jsonOut, tmpl, stdout, stderr)
+	}
+
 	res, err := cnnfag.Get(ctx)
 	if err != nil {
 		fmt.Fprintln(stderr, "cnnfag:", err)
@@ -50,9 +133,14 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 	}
 
 	if *jsonOut {
-		enc := json.NewEncoder(stdout)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(res); err != nil {
+		if err := writeJSON(stdout, res); err != nil {
+			fmt.Fprintln(stderr, "cnnfag:", err)
+			return 1
+		}
+		return 0
+	}
+	if tmpl != nil {
+		if err := executeTemplate(stdout, tmpl, res); err != nil {
 			fmt.Fprintln(stderr, "cnnfag:", err)
 			return 1
 		}
@@ -64,3 +152,41 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 		res.PreviousClose, res.OneWeekAgo, res.OneMonthAgo, res.OneYearAgo)
 	return 0
 }
+
+// printSnapshot prints an index other than CNN's. Providers share only the
+// score, rating and history, so there are no past-period values to show.
+func printSnapshot(ctx context.Context, name string, jsonOut bool, tmpl *template.Template, stdout, stderr io.Writer) int {
+	p, ok := cnnfag.LookupProvider(name)
+	if !ok {
+		fmt.Fprintf(stderr, "cnnfag: unknown provider %q, the providers are %s\n", name, strings.Join(cnnfag.ProviderNames(), ", "))
+		return 2
+	}
+	snap, err := p.Snapshot(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag:", err)
+		return 1
+	}
+
+	if jsonOut {
+		if err := writeJSON(stdout, snap); err != nil {
+			fmt.Fprintln(stderr, "cnnfag:", err)
+			return 1
+		}
+		return 0
+	}
+	if tmpl != nil {
+		if err := executeTemplate(stdout, tmpl, snap); err != nil {
+			fmt.Fprintln(stderr, "cnnfag:", err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintf(stdout, "%.0f (%s) as of %s\n", snap.Score, snap.Rating, snap.Timestamp.Format(time.RFC3339))
+	return 0
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -65,6 +65,39 @@ func TestRun(t *testing.T) {
 	}
 	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
 
+	// -provider prints any registered index.
+	registerProvider(t, "static", staticProvider{Provider: "static", Score: 81, Rating: "extreme greed"})
+	stdout.Reset()
+	if code := run([]string{"-provider", "static"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(-provider static) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "81 (extreme greed)") {
+		t.Errorf("provider output: %q", stdout.String())
+	}
+	if code := run([]string{"-provider", "nope"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("run(-provider nope) = %d, want 2", code)
+	}
+	// The commands read CNN only, so they refuse another provider rather
+	// than print CNN's index in its place.
+	stdout.Reset()
+	for _, args := range [][]string{{"-provider", "static", "history"}, {"-provider", "crypto", "chart"}} {
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 2 || stdout.Len() != 0 {
+			t.Errorf("run(%q) = %d, printed %q; want 2 and nothing", args, code, stdout.String())
+		}
+	}
+	if code := run([]string{"-provider", "cnn", "indicators"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Errorf("run(-provider cnn indicators) = %d, want 0", code)
+	}
+	// So do the output flags, which only shape the plain print.
+	stdout.Reset()
+	for _, args := range [][]string{{"-json", "history"}, {"-format", "{{.Score}}", "history"}, {"-template-file", "x.tmpl", "gauge"}} {
+		stderr.Reset()
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 2 || stdout.Len() != 0 ||
+			!strings.Contains(stderr.String(), "works only without a command") {
+			t.Errorf("run(%q) = %d, printed %q, stderr %q; want 2 and nothing", args, code, stdout.String(), stderr.String())
+		}
+	}
+
 	// The mcp subcommand wires stdin/stdout to serveMCP.
 	stdout.Reset()
 	in := `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"
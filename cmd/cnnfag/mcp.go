@@ -5,19 +5,33 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"reflect"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
 )
 
 // The MCP stdio transport is JSON-RPC 2.0, one message per line. A server with
-// a single argument-light tool needs only initialize, ping, tools/list and
-// tools/call, which is small enough to implement on the standard library.
-// Not implemented: cancellation, progress, and every server-to-client feature.
+// a few tools, prompts and read-only resources needs only initialize, ping,
+// the list methods, tools/call, prompts/get and resources/read, which is
+// small enough to implement on the standard library, plus resource
+// subscriptions, whose update notifications are the one message the server
+// sends unasked along with log messages and progress. Requests run
+// concurrently and can be cancelled. Not implemented: the server-to-client
+// requests, such as sampling.
 
-const toolName = "get_fear_and_greed"
+const (
+	toolName         = "get_fear_and_greed"
+	providerToolName = "get_sentiment_index"
+	historyToolName  = "get_history"
+
+	statisticsToolName = "get_statistics"
+	compareToolName    = "compare_periods"
+)
 
 // Protocol revisions this server knows. An initialize request asking for
 // anything else is answered with the newest of these, as the spec directs.
@@ -29,6 +43,10 @@ var protocolVersions = map[string]bool{
 
 const latestProtocolVersion = "2025-06-18"
 
+// structuredSince is the first protocol revision with tool output schemas
+// and structured results. Revisions are dates, so they compare as strings.
+const structuredSince = "2025-06-18"
+
 type rpcRequest struct {
 	ID     json.RawMessage `json:"id"`
 	Method string          `json:"method"`
@@ -52,14 +70,18 @@ type textContent struct {
 	Text string `json:"text"`
 }
 
+// toolResult carries the result as text for every client and, from
+// protocol 2025-06-18, as structuredContent matching the tool's outputSchema.
 type toolResult struct {
-	Content []textContent `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content           []textContent `json:"content"`
+	StructuredContent any           `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
 }
 
 var toolDef = map[string]any{
-	"name":        toolName,
-	"description": "CNN's Fear & Greed index for the US stock market: current score (0-100) and rating, values for the previous close, week, month and year, and the seven component indicators (market momentum, stock price strength and breadth, put/call options, volatility, junk bond and safe haven demand), each with its own score and rating.",
+	"name":         toolName,
+	"description":  "CNN's Fear & Greed index for the US stock market: current score (0-100) and rating, values for the previous close, week, month and year, and the seven component indicators (market momentum, stock price strength and breadth, put/call options, volatility, junk bond and safe haven demand), each with its own score and rating.",
+	"outputSchema": schemaOf(reflect.TypeOf(cnnfag.Result{})),
 	"inputSchema": map[string]any{
 		"type": "object",
 		"properties": map[string]any{
@@ -72,11 +94,189 @@ var toolDef = map[string]any{
 	},
 }
 
+// providerToolDef describes the tool that reads any registered provider. It is
+// built per tools/list so the enum covers providers registered at run time.
+func providerToolDef() map[string]any {
+	return map[string]any{
+		"name":         providerToolName,
+		"description":  "A fear and greed style sentiment index by provider: \"cnn\" is CNN's index for the US stock market, \"crypto\" is alternative.me's index for the crypto market. Returns the current score (0-100), rating and timestamp. For CNN's past-period values and component indicators use " + toolName + ".",
+		"outputSchema": schemaOf(reflect.TypeOf(cnnfag.Snapshot{})),
+		"inputSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"provider": map[string]any{
+					"type":        "string",
+					"enum":        cnnfag.ProviderNames(),
+					"description": "Which index to read.",
+				},
+				"include_history": map[string]any{
+					"type":        "boolean",
+					"description": "Include the daily history the provider serves. Off by default to keep the response small.",
+				},
+			},
+			"required":             []string{"provider"},
+			"additionalProperties": false,
+		},
+	}
+}
+
+var historyToolDef = map[string]any{
+	"name":         historyToolName,
+	"description":  "Daily history of CNN's Fear & Greed index or one of its component indicators between two dates, optionally resampled to weekly or monthly closes to keep long ranges short. Index values are 0-100 scores; indicator values are the raw series (the S&P 500 level for momentum, the VIX level for vix, ratios and spreads for the rest). About a year of history is available.",
+	"outputSchema": schemaOf(reflect.TypeOf(historyResult{})),
+	"inputSchema": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"series": seriesProperty,
+			"start": map[string]any{
+				"type":        "string",
+				"format":      "date",
+				"description": "First date to include, YYYY-MM-DD.",
+			},
+			"end": map[string]any{
+				"type":        "string",
+				"format":      "date",
+				"description": "Last date to include, YYYY-MM-DD. Defaults to the latest value.",
+			},
+			"resample": map[string]any{
+				"type":        "string",
+				"enum":        []string{string(cnnfag.Weekly), string(cnnfag.Monthly)},
+				"description": "Return only the last value of each week or month.",
+			},
+		},
+		"required":             []string{"series", "start"},
+		"additionalProperties": false,
+	},
+}
+
+// tools returns the tool definitions, built per call so the provider enum
+// covers providers registered at run time.
+func tools() []map[string]any {
+	return []map[string]any{toolDef, providerToolDef(), historyToolDef, statisticsToolDef, compareToolDef}
+}
+
+// toolsFor returns the tool definitions for a protocol version, without
+// output schemas before structuredSince.
+func toolsFor(version string) []map[string]any {
+	defs := tools()
+	if version >= structuredSince {
+		return defs
+	}
+	for i, def := range defs {
+		trimmed := make(map[string]any, len(def))
+		for k, v := range def {
+			if k != "outputSchema" {
+				trimmed[k] = v
+			}
+		}
+		defs[i] = trimmed
+	}
+	return defs
+}
+
 func serveMCP(r io.Reader, w io.Writer, fetch func(context.Context) (cnnfag.Result, error)) error {
+	return newMCPSession(newResultCache(fetch, mcpCacheTTL)).serve(r, &mcpWriter{enc: json.NewEncoder(w)})
+}
+
+// messageSink takes the messages the server sends: the stdio stream, or
+// over HTTP a session's event stream or the response to one POST.
+type messageSink interface {
+	send(v any) error
+}
+
+// mcpWriter serializes the messages of concurrent requests and of
+// background work such as the subscription poller onto one stream, a line
+// each. After a write fails it writes nothing more and keeps returning the
+// error.
+type mcpWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+func (w *mcpWriter) send(v any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = w.enc.Encode(v)
+	}
+	return w.err
+}
+
+func (w *mcpWriter) failed() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// rpcNotification is a JSON-RPC message that expects no answer.
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// requestTimeout bounds each request, fetch included.
+const requestTimeout = 30 * time.Second
+
+// errCancelled is the cause of a request context cancelled by the client.
+var errCancelled = errors.New("request cancelled by the client")
+
+// mcpSession is the state of one client connection, whatever the transport.
+type mcpSession struct {
+	fetch func(context.Context) (cnnfag.Result, error)
+	// out takes the messages that belong to no request, such as resource
+	// updates.
+	out messageSink
+
+	pollEvery time.Duration
+	after     func(time.Duration) <-chan time.Time // time.After, swapped in tests
+
+	ctx      context.Context // done when the session ends
+	stop     context.CancelFunc
+	requests sync.WaitGroup // requests in flight
+	wg       sync.WaitGroup // background goroutines
+	mu       sync.Mutex
+	// The negotiated protocol version. Until initialize, assume the oldest
+	// and send only what every revision understands.
+	version  string
+	logLevel string                             // empty for defaultLogLevel
+	inflight map[string]context.CancelCauseFunc // by request ID
+	subs     map[string]bool                    // subscribed resource URIs
+	polling  bool
+}
+
+// newMCPSession returns a session that fetches through cache, which
+// sessions may share.
+func newMCPSession(cache *resultCache) *mcpSession {
+	s := &mcpSession{pollEvery: subscriptionPoll, after: time.After}
+	s.fetch = s.observe(cache)
+	return s
+}
+
+// start begins the session; background work runs until close.
+func (s *mcpSession) start(out messageSink) {
+	s.out = out
+	s.ctx, s.stop = context.WithCancel(context.Background())
+}
+
+// close waits for the requests in flight to be answered, then stops
+// background work and waits for it too, so the session sends nothing after
+// close returns.
+func (s *mcpSession) close() {
+	s.requests.Wait()
+	s.stop()
+	s.wg.Wait()
+}
+
+// serve runs the session over the stdio transport: it reads messages from
+// r until EOF and writes every answer to w.
+func (s *mcpSession) serve(r io.Reader, w *mcpWriter) error {
+	s.start(w)
+	defer s.close()
+
 	sc := bufio.NewScanner(r)
 	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
-	enc := json.NewEncoder(w)
-
 	for sc.Scan() {
 		line := bytes.TrimSpace(sc.Bytes())
 		if len(line) == 0 {
@@ -85,88 +285,315 @@ func serveMCP(r io.Reader, w io.Writer, fetch func(context.Context) (cnnfag.Resu
 
 		var req rpcRequest
 		if err := json.Unmarshal(line, &req); err != nil {
-			if err := enc.Encode(rpcResponse{JSONRPC: "2.0", Error: &rpcError{-32700, "parse error"}}); err != nil {
-				return err
-			}
-			continue
+			w.send(rpcResponse{JSONRPC: "2.0", Error: &rpcError{-32700, "parse error"}})
+		} else {
+			s.receive(req, w, nil)
 		}
-		if len(req.ID) == 0 || string(req.ID) == "null" {
-			continue // a notification, nothing to answer
+		if err := w.failed(); err != nil {
+			return err
 		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	s.requests.Wait()
+	return w.failed()
+}
+
+// receive handles one message from the client. Requests are answered to
+// reply, each in its own goroutine so a slow fetch does not hold up a ping;
+// initialize and notifications are handled in order as they arrive, so the
+// requests after initialize see the negotiated version. finished, if not
+// nil, is called once the message is dealt with: a request answered or
+// cancelled.
+func (s *mcpSession) receive(req rpcRequest, reply messageSink, finished func()) {
+	switch {
+	case len(req.ID) == 0 || string(req.ID) == "null":
+		s.notification(req)
+	case req.Method == "initialize":
+		reply.send(s.handle(context.Background(), req))
+	default:
+		s.dispatch(req, reply, finished)
+		return
+	}
+	if finished != nil {
+		finished()
+	}
+}
+
+type replyKey struct{}
 
-		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
-		switch req.Method {
-		case "initialize":
-			resp.Result = initializeResult(req.Params)
-		case "ping":
-			resp.Result = struct{}{}
-		case "tools/list":
-			resp.Result = map[string]any{"tools": []any{toolDef}}
-		case "tools/call":
-			resp.Result, resp.Error = callTool(req.Params, fetch)
-		default:
-			resp.Error = &rpcError{-32601, "method not found: " + req.Method}
+// replyFrom returns where the messages of the request ctx belongs to go, or
+// nil outside a request.
+func replyFrom(ctx context.Context) messageSink {
+	sink, _ := ctx.Value(replyKey{}).(messageSink)
+	return sink
+}
+
+// dispatch answers req in a goroutine with a context that
+// notifications/cancelled can cancel. A cancelled request gets no response,
+// as the spec directs.
+func (s *mcpSession) dispatch(req rpcRequest, reply messageSink, finished func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	ctx = context.WithValue(ctx, replyKey{}, reply)
+	ctx = withProgress(ctx, reply, req.Params)
+	id := string(req.ID)
+	s.mu.Lock()
+	if s.inflight == nil {
+		s.inflight = map[string]context.CancelCauseFunc{}
+	}
+	s.inflight[id] = cancel
+	s.mu.Unlock()
+
+	s.requests.Add(1)
+	go func() {
+		defer s.requests.Done()
+		resp := s.handle(ctx, req)
+
+		s.mu.Lock()
+		delete(s.inflight, id)
+		s.mu.Unlock()
+		cancelled := context.Cause(ctx) == errCancelled
+		cancel(nil)
+		if !cancelled {
+			reply.send(resp)
+		}
+		if finished != nil {
+			finished()
 		}
+	}()
+}
 
-		if err := enc.Encode(resp); err != nil {
-			return err
+// notification handles a message that expects no answer. Only cancellation
+// needs handling; the client's initialized notification and any others are
+// ignored.
+func (s *mcpSession) notification(req rpcRequest) {
+	if req.Method != "notifications/cancelled" {
+		return
+	}
+	var p struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if json.Unmarshal(req.Params, &p) != nil {
+		return
+	}
+	s.mu.Lock()
+	cancel := s.inflight[string(p.RequestID)]
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel(errCancelled)
+	}
+}
+
+func (s *mcpSession) handle(ctx context.Context, req rpcRequest) rpcResponse {
+	s.mu.Lock()
+	version := s.version
+	s.mu.Unlock()
+
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	switch req.Method {
+	case "initialize":
+		version = negotiateVersion(req.Params)
+		s.mu.Lock()
+		s.version = version
+		s.mu.Unlock()
+		resp.Result = initializeResult(version)
+	case "ping":
+		resp.Result = struct{}{}
+	case "tools/list":
+		resp.Result = map[string]any{"tools": toolsFor(version)}
+	case "resources/list":
+		resp.Result = map[string]any{"resources": resourceList()}
+	case "resources/templates/list":
+		resp.Result = map[string]any{"resourceTemplates": resourceTemplates}
+	case "resources/read":
+		resp.Result, resp.Error = readResource(ctx, req.Params, s.fetch)
+	case "resources/subscribe":
+		resp.Result, resp.Error = s.subscribe(req.Params)
+	case "resources/unsubscribe":
+		resp.Result, resp.Error = s.unsubscribe(req.Params)
+	case "logging/setLevel":
+		resp.Result, resp.Error = s.setLevel(req.Params)
+	case "prompts/list":
+		resp.Result = map[string]any{"prompts": prompts}
+	case "prompts/get":
+		resp.Result, resp.Error = getPrompt(ctx, req.Params, s.fetch)
+	case "tools/call":
+		resp.Result, resp.Error = callTool(ctx, req.Params, s.fetch)
+		if r, ok := resp.Result.(toolResult); ok && version < structuredSince {
+			r.StructuredContent = nil
+			resp.Result = r
 		}
+	default:
+		resp.Error = &rpcError{-32601, "method not found: " + req.Method}
 	}
-	return sc.Err()
+	return resp
 }
 
-func initializeResult(params json.RawMessage) any {
+// negotiateVersion picks the protocol version to answer initialize with.
+func negotiateVersion(params json.RawMessage) string {
 	var p struct {
 		ProtocolVersion string `json:"protocolVersion"`
 	}
 	json.Unmarshal(params, &p) // on any error the fallback below applies
 
-	version := p.ProtocolVersion
-	if !protocolVersions[version] {
-		version = latestProtocolVersion
+	if !protocolVersions[p.ProtocolVersion] {
+		return latestProtocolVersion
 	}
+	return p.ProtocolVersion
+}
+
+func initializeResult(version string) any {
 	return map[string]any{
 		"protocolVersion": version,
-		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"capabilities":    map[string]any{"tools": map[string]any{}, "resources": map[string]any{"subscribe": true}, "prompts": map[string]any{}, "logging": map[string]any{}},
 		"serverInfo":      map[string]any{"name": "cnnfag", "version": buildVersion()},
 	}
 }
 
-func callTool(params json.RawMessage, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
+// callTool validates the arguments against the tool's input schema, so
+// every tool gets the same invalid-params errors, and runs the tool.
+func callTool(ctx context.Context, params json.RawMessage, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
 	var p struct {
-		Name      string `json:"name"`
-		Arguments struct {
-			IncludeHistory bool `json:"include_history"`
-		} `json:"arguments"`
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{-32602, "invalid params"}
+	}
+	var def map[string]any
+	for _, t := range tools() {
+		if t["name"] == p.Name {
+			def = t
+		}
 	}
-	if err := json.Unmarshal(params, &p); err != nil || p.Name != toolName {
-		return nil, &rpcError{-32602, "unknown tool"}
+	if def == nil {
+		return nil, &rpcError{-32602, "unknown tool: " + p.Name}
 	}
+	if err := validateArgs(def["inputSchema"].(map[string]any), p.Arguments); err != nil {
+		return nil, &rpcError{-32602, "invalid arguments: " + err.Error()}
+	}
+	var args toolArgs
+	json.Unmarshal(p.Arguments, &args) // validated above
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 
+	switch p.Name {
+	case providerToolName:
+		provider, _ := cnnfag.LookupProvider(args.Provider)
+		snap, err := provider.Snapshot(ctx)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		if !args.IncludeHistory {
+			snap.History = nil
+		}
+		return jsonResult(snap)
+
+	case historyToolName:
+		return getHistory(ctx, args, fetch)
+	case statisticsToolName:
+		return getStatistics(ctx, args, fetch)
+	case compareToolName:
+		return comparePeriods(ctx, args, fetch)
+	}
+
 	res, err := fetch(ctx)
 	if err != nil {
-		// A fetch failure is a tool-level error: the model should see it.
-		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
-	}
-	if !p.Arguments.IncludeHistory {
-		res.History = nil
-		for _, ind := range []*cnnfag.Indicator{
-			&res.MarketMomentum, &res.StockPriceStrength, &res.StockPriceBreadth,
-			&res.PutCallOptions, &res.MarketVolatility, &res.JunkBondDemand,
-			&res.SafeHavenDemand,
-		} {
-			ind.History = nil
+		return errorResult(err), nil
+	}
+	if !args.IncludeHistory {
+		res = withoutHistory(res)
+	}
+	return jsonResult(res)
+}
+
+// withoutHistory drops the daily series of the index and every indicator.
+func withoutHistory(res cnnfag.Result) cnnfag.Result {
+	res.History = nil
+	for _, ind := range []*cnnfag.Indicator{
+		&res.MarketMomentum, &res.StockPriceStrength, &res.StockPriceBreadth,
+		&res.PutCallOptions, &res.MarketVolatility, &res.JunkBondDemand,
+		&res.SafeHavenDemand,
+	} {
+		ind.History = nil
+	}
+	return res
+}
+
+// toolArgs holds the arguments of every tool; each uses its own subset.
+type toolArgs struct {
+	Provider       string `json:"provider"`
+	IncludeHistory bool   `json:"include_history"`
+	Series         string `json:"series"`
+	Start          string `json:"start"`
+	End            string `json:"end"`
+	Resample       string `json:"resample"`
+	From           string `json:"from"`
+	To             string `json:"to"`
+}
+
+// historyResult is what get_history returns.
+type historyResult struct {
+	Series   string         `json:"series"`
+	Resample string         `json:"resample,omitempty"`
+	Values   []cnnfag.Value `json:"values"`
+}
+
+func getHistory(ctx context.Context, args toolArgs, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
+	start, end, rerr := toolWindow(args.Start, args.End)
+	if rerr != nil {
+		return nil, rerr
+	}
+	res, err := fetch(ctx)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	series, _ := res.Series(args.Series)
+	values := cnnfag.Between(series, start, end)
+	if args.Resample != "" {
+		values = cnnfag.Resample(values, cnnfag.Period(args.Resample))
+	}
+	if values == nil {
+		values = []cnnfag.Value{}
+	}
+	return jsonResult(historyResult{Series: args.Series, Resample: args.Resample, Values: values})
+}
+
+// toolWindow parses the validated start and end dates of a window for
+// cnnfag.Between; either may be empty to leave that side open. The end date
+// is taken inclusively, through the newest value, which is stamped with the
+// time of CNN's update rather than midnight.
+func toolWindow(start, end string) (time.Time, time.Time, *rpcError) {
+	var from, to time.Time
+	if start != "" {
+		from, _ = time.Parse(time.DateOnly, start)
+	}
+	if end != "" {
+		to, _ = time.Parse(time.DateOnly, end)
+		if to.Before(from) {
+			return from, to, &rpcError{-32602, "invalid arguments: end is before start"}
 		}
+		to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
 	}
+	return from, to, nil
+}
+
+// errorResult reports a fetch failure as a tool-level error: the model should
+// see it.
+func errorResult(err error) toolResult {
+	return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}
+}
 
-	text, err := json.MarshalIndent(res, "", "  ")
+func jsonResult(v any) (any, *rpcError) {
+	text, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return nil, &rpcError{-32603, err.Error()}
 	}
-	return toolResult{Content: []textContent{{Type: "text", Text: string(text)}}}, nil
+	// The text stays for clients that ignore structuredContent, as the
+	// spec recommends.
+	return toolResult{Content: []textContent{{Type: "text", Text: string(text)}}, StructuredContent: v}, nil
 }
 
 func buildVersion() string {
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -27,9 +31,11 @@ func TestServeMCP(t *testing.T) {
 		}, nil
 	}
 
+	registerProvider(t, "static", staticProvider{Provider: "static", Score: 12.5, Rating: "extreme fear"})
+
 	in := strings.Join([]string{
 		`not json at all`,
-		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+		`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
 		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"0"}}}`,
 		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
 		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
@@ -38,6 +44,14 @@ func TestServeMCP(t *testing.T) {
 		`{"jsonrpc":"2.0","id":5,"method":"no/such/method"}`,
 		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"wrong_tool"}}`,
 		`{"jsonrpc":"2.0","id":7,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"get_sentiment_index","arguments":{"provider":"static"}}}`,
+		`{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"get_sentiment_index","arguments":{"provider":"nope"}}}`,
+		`{"jsonrpc":"2.0","id":10,"method":"tools/call","params":{"name":"get_history","arguments":{"series":"vix","start":"2025-08-01","resample":"monthly"}}}`,
+		`{"jsonrpc":"2.0","id":11,"method":"tools/call","params":{"name":"get_history","arguments":{"series":"index","start":"2025-09-01"}}}`,
+		`{"jsonrpc":"2.0","id":12,"method":"tools/call","params":{"name":"get_history","arguments":{"series":"vix","start":"August"}}}`,
+		`{"jsonrpc":"2.0","id":13,"method":"tools/call","params":{"name":"get_history","arguments":{"series":"vix","start":"2025-08-01","limit":5}}}`,
+		`{"jsonrpc":"2.0","id":14,"method":"tools/call","params":{"name":"get_history","arguments":{"series":"vix","start":"2025-08-01","end":"2025-07-01"}}}`,
+		`{"jsonrpc":"2.0","id":15,"method":"tools/call","params":{"name":"get_fear_and_greed","arguments":{"include_history":"yes"}}}`,
 	}, "\n") + "\n"
 
 	var out strings.Builder
@@ -45,9 +59,9 @@ func TestServeMCP(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
-	if len(lines) != 9 {
-		t.Fatalf("got %d responses, want 9 (the notification must not be answered):\n%s", len(lines), out.String())
+	lines := responses(t, out.String())
+	if len(lines) != 17 {
+		t.Fatalf("got %d responses, want 17 (the notification must not be answered):\n%s", len(lines), out.String())
 	}
 
 	var resp struct {
@@ -59,69 +73,279 @@ func TestServeMCP(t *testing.T) {
 	}
 
 	// A line that is not JSON gets a parse error.
-	mustUnmarshal(t, lines[0], &resp)
+	mustUnmarshal(t, lines[""], &resp)
 	if resp.Error == nil || resp.Error.Code != -32700 {
-		t.Errorf("parse error response: %s", lines[0])
+		t.Errorf("parse error response: %s", lines[""])
 	}
 
 	// An unsupported protocol version falls back to the latest we know.
-	mustUnmarshal(t, lines[1], &resp)
+	mustUnmarshal(t, lines["0"], &resp)
 	if !strings.Contains(string(resp.Result), `"protocolVersion":"2025-06-18"`) {
-		t.Errorf("initialize fallback response: %s", lines[1])
+		t.Errorf("initialize fallback response: %s", lines["0"])
 	}
 
 	// initialize echoes a supported protocol version.
-	mustUnmarshal(t, lines[2], &resp)
+	mustUnmarshal(t, lines["1"], &resp)
 	if !strings.Contains(string(resp.Result), `"protocolVersion":"2025-03-26"`) {
-		t.Errorf("initialize response: %s", lines[2])
+		t.Errorf("initialize response: %s", lines["1"])
 	}
 
-	// tools/list advertises exactly our tool.
-	mustUnmarshal(t, lines[3], &resp)
+	// tools/list advertises exactly our tools, with registered providers
+	// in the provider enum.
+	mustUnmarshal(t, lines["2"], &resp)
 	var list struct {
 		Tools []struct {
 			Name string `json:"name"`
 		} `json:"tools"`
 	}
 	mustUnmarshal(t, string(resp.Result), &list)
-	if len(list.Tools) != 1 || list.Tools[0].Name != "get_fear_and_greed" {
-		t.Errorf("tools/list response: %s", lines[1])
+	if len(list.Tools) != 5 || list.Tools[0].Name != "get_fear_and_greed" || list.Tools[1].Name != "get_sentiment_index" ||
+		list.Tools[2].Name != "get_history" || list.Tools[3].Name != "get_statistics" || list.Tools[4].Name != "compare_periods" ||
+		!strings.Contains(string(resp.Result), `"static"`) {
+		t.Errorf("tools/list response: %s", lines["2"])
+	}
+	// 2025-03-26 predates output schemas and structured results.
+	if strings.Contains(out.String(), "outputSchema") || strings.Contains(out.String(), "structuredContent") {
+		t.Errorf("a 2025-03-26 session got 2025-06-18 fields:\n%s", out.String())
 	}
 
 	// tools/call returns the score and the indicators, and omits every
 	// history by default.
-	mustUnmarshal(t, lines[4], &resp)
+	mustUnmarshal(t, lines["3"], &resp)
 	if !strings.Contains(string(resp.Result), "43.71") ||
 		!strings.Contains(string(resp.Result), "marketVolatility") ||
 		strings.Contains(string(resp.Result), "history") ||
 		strings.Contains(string(resp.Result), "17.5") {
-		t.Errorf("tools/call without history: %s", lines[4])
+		t.Errorf("tools/call without history: %s", lines["3"])
 	}
 
 	// include_history brings the daily points in, for the index and the
 	// indicators both.
-	mustUnmarshal(t, lines[5], &resp)
+	mustUnmarshal(t, lines["4"], &resp)
 	if !strings.Contains(string(resp.Result), "60.2") || !strings.Contains(string(resp.Result), "17.5") {
-		t.Errorf("tools/call with history: %s", lines[5])
+		t.Errorf("tools/call with history: %s", lines["4"])
 	}
 
 	// Unknown methods get a JSON-RPC error.
-	mustUnmarshal(t, lines[6], &resp)
+	mustUnmarshal(t, lines["5"], &resp)
 	if resp.Error == nil || resp.Error.Code != -32601 {
-		t.Errorf("unknown method response: %s", lines[6])
+		t.Errorf("unknown method response: %s", lines["5"])
 	}
 
 	// Calling a tool we do not have is an invalid-params error.
-	mustUnmarshal(t, lines[7], &resp)
+	mustUnmarshal(t, lines["6"], &resp)
 	if resp.Error == nil || resp.Error.Code != -32602 {
-		t.Errorf("unknown tool response: %s", lines[7])
+		t.Errorf("unknown tool response: %s", lines["6"])
 	}
 
 	// ping answers with an empty result.
-	mustUnmarshal(t, lines[8], &resp)
+	mustUnmarshal(t, lines["7"], &resp)
 	if string(resp.Result) != "{}" {
-		t.Errorf("ping response: %s", lines[8])
+		t.Errorf("ping response: %s", lines["7"])
+	}
+
+	// get_sentiment_index reads any registered provider.
+	mustUnmarshal(t, lines["8"], &resp)
+	if !strings.Contains(string(resp.Result), `\"provider\": \"static\"`) || !strings.Contains(string(resp.Result), "12.5") {
+		t.Errorf("get_sentiment_index response: %s", lines["8"])
+	}
+	mustUnmarshal(t, lines["9"], &resp)
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("unknown provider response: %s", lines["9"])
+	}
+
+	// get_history returns the slice of one series, and an empty list when
+	// the range holds no values.
+	mustUnmarshal(t, lines["10"], &resp)
+	if !strings.Contains(string(resp.Result), "17.5") || !strings.Contains(string(resp.Result), `\"resample\": \"monthly\"`) {
+		t.Errorf("get_history response: %s", lines["10"])
+	}
+	mustUnmarshal(t, lines["11"], &resp)
+	if !strings.Contains(string(resp.Result), `\"values\": []`) {
+		t.Errorf("get_history empty range response: %s", lines["11"])
+	}
+
+	// Arguments that do not match the input schema are invalid params:
+	// a malformed date, an unknown argument, an inverted range and a
+	// wrongly typed flag.
+	for _, id := range []string{"12", "13", "14", "15"} {
+		line := lines[id]
+		mustUnmarshal(t, line, &resp)
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("invalid arguments response: %s", line)
+		}
+	}
+}
+
+func TestServeMCPStructured(t *testing.T) {
+	fetch := func(ctx context.Context) (cnnfag.Result, error) {
+		return cnnfag.Result{Source: "api", Score: 43.71, Rating: "fear", History: []cnnfag.Point{
+			{Date: time.Date(2026, 8, 11, 0, 0, 0, 0, time.UTC), Score: 43.71, Rating: "fear"},
+		}}, nil
+	}
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_fear_and_greed"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"get_statistics","arguments":{"series":"index"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"resources/templates/list"}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/read","params":{"uri":"fng://current"}}`,
+	}, "\n") + "\n"
+
+	var out strings.Builder
+	if err := serveMCP(strings.NewReader(in), &out, fetch); err != nil {
+		t.Fatal(err)
+	}
+	lines := responses(t, out.String())
+
+	var list struct {
+		Result struct {
+			Tools []struct {
+				Name         string `json:"name"`
+				OutputSchema struct {
+					Type     string   `json:"type"`
+					Required []string `json:"required"`
+				} `json:"outputSchema"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	mustUnmarshal(t, lines["2"], &list)
+	schemas := map[string][]string{}
+	for _, tool := range list.Result.Tools {
+		if tool.OutputSchema.Type != "object" {
+			t.Errorf("%s has no object outputSchema", tool.Name)
+		}
+		schemas[tool.Name] = tool.OutputSchema.Required
+	}
+
+	// Each result carries its value as structuredContent too, with every
+	// property its outputSchema requires.
+	for i, name := range []string{"get_fear_and_greed", "get_statistics"} {
+		var resp struct {
+			Result struct {
+				Content           []textContent  `json:"content"`
+				StructuredContent map[string]any `json:"structuredContent"`
+			} `json:"result"`
+		}
+		mustUnmarshal(t, lines[strconv.Itoa(3+i)], &resp)
+		if len(resp.Result.Content) != 1 || resp.Result.StructuredContent == nil {
+			t.Errorf("%s result: %s", name, lines[strconv.Itoa(3+i)])
+			continue
+		}
+		for _, key := range schemas[name] {
+			if _, ok := resp.Result.StructuredContent[key]; !ok {
+				t.Errorf("%s structuredContent lacks required %q", name, key)
+			}
+		}
+	}
+	if !strings.Contains(lines["3"], `"structuredContent":{"source":"api","score":43.71`) {
+		t.Errorf("get_fear_and_greed result: %s", lines["3"])
+	}
+
+	// The resources methods are wired up, and resources and prompts
+	// advertised.
+	if !strings.Contains(lines["1"], `"prompts":{},"resources":{"subscribe":true}`) {
+		t.Errorf("initialize response: %s", lines["1"])
+	}
+	if !strings.Contains(lines["5"], `"uriTemplate":"fng://history/{date}"`) {
+		t.Errorf("resources/templates/list response: %s", lines["5"])
+	}
+	if !strings.Contains(lines["6"], `"mimeType":"text/plain"`) {
+		t.Errorf("resources/read response: %s", lines["6"])
+	}
+}
+
+func TestServeMCPConcurrent(t *testing.T) {
+	// Fetches block until released or cancelled.
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (cnnfag.Result, error) {
+		select {
+		case <-release:
+			return cnnfag.Result{Score: 43.71, Rating: "fear"}, nil
+		case <-ctx.Done():
+			return cnnfag.Result{}, ctx.Err()
+		}
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := serveMCP(inR, outW, fetch)
+		outW.Close()
+		done <- err
+	}()
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(outR)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+
+	// A ping is answered while a tool call waits on its fetch.
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_fear_and_greed"}}`)
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	if got := <-lines; got != `{"jsonrpc":"2.0","id":2,"result":{}}` {
+		t.Fatalf("got %s, want the ping response", got)
+	}
+
+	// Cancelling the call ends its fetch, and it is not answered.
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1,"reason":"user gave up"}}`)
+
+	// At EOF, a call in flight is still answered before serveMCP returns.
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_fear_and_greed"}}`)
+	inW.Close()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("serveMCP returned with a request in flight: %v", err)
+	default:
+	}
+	close(release)
+
+	var rest []string
+	for line := range lines {
+		rest = append(rest, line)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || !strings.Contains(rest[0], `"id":3`) || !strings.Contains(rest[0], "43.71") {
+		t.Errorf("after the ping got %q, want only the response to request 3", rest)
+	}
+}
+
+type staticProvider cnnfag.Snapshot
+
+func (p staticProvider) Snapshot(context.Context) (cnnfag.Snapshot, error) {
+	return cnnfag.Snapshot(p), nil
+}
+
+// registerProvider registers p under name for the rest of the test.
+func registerProvider(t *testing.T, name string, p cnnfag.Provider) {
+	cnnfag.RegisterProvider(name, p)
+	t.Cleanup(func() { cnnfag.UnregisterProvider(name) })
+}
+
+// responses indexes serveMCP's output by response ID, since concurrent
+// requests are answered in any order. The response to a line that did not
+// parse has no ID and is under "".
+func responses(t *testing.T, out string) map[string]string {
+	t.Helper()
+	byID := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var resp struct {
+			ID json.RawMessage `json:"id"`
+		}
+		mustUnmarshal(t, line, &resp)
+		if _, dup := byID[string(resp.ID)]; dup {
+			t.Fatalf("two responses with ID %s:\n%s", resp.ID, out)
+		}
+		byID[string(resp.ID)] = line
 	}
+	return byID
 }
 
 func mustUnmarshal(t *testing.T, data string, v any) {
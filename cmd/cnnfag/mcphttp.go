@@ -0,0 +1,510 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// runMCP runs the MCP server over stdio, or with -http over the Streamable
+// HTTP transport, so one process can serve several clients.
+func runMCP(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag mcp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("http", "", "serve the Streamable HTTP transport at /mcp on `address` instead of stdio")
+	allow := fs.String("allow-origin", "", "with -http, comma-separated `origins` browsers may connect from besides localhost")
+	tokenFile := fs.String("token-file", "", "with -http, require a bearer token listed in `file`, one per line")
+	jwks := fs.String("jwks", "", "with -http, accept OAuth access tokens signed by the keys in this JWKS `file or URL`")
+	issuer := fs.String("issuer", "", "with -jwks, the authorization server `URL` tokens must come from")
+	resource := fs.String("resource", "", "with -jwks, this server's canonical `URI`, which tokens must name as audience")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *addr == "" {
+		if *allow != "" || *tokenFile != "" || *jwks != "" || *issuer != "" || *resource != "" {
+			fmt.Fprintln(stderr, "cnnfag mcp: -allow-origin and the auth flags need -http")
+			return 2
+		}
+		if err := serveMCP(stdin, stdout, cnnfag.Get); err != nil {
+			fmt.Fprintln(stderr, "cnnfag mcp:", err)
+			return 1
+		}
+		return 0
+	}
+
+	var origins []string
+	if *allow != "" {
+		origins = strings.Split(*allow, ",")
+	}
+	auth, err := newMCPAuth(*tokenFile, *jwks, *issuer, *resource)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag mcp:", err)
+		return 2
+	}
+	h := newMCPHandler(newResultCache(cnnfag.Get, mcpCacheTTL), origins)
+	srv := &http.Server{Addr: *addr, Handler: mcpMux(h, auth), ReadHeaderTimeout: 10 * time.Second}
+	// Open event streams last as long as their sessions, so shutting down
+	// ends the sessions.
+	srv.RegisterOnShutdown(h.closeAll)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), requestTimeout+5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintln(stderr, "cnnfag mcp: listening on", *addr+"/mcp")
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, "cnnfag mcp:", err)
+		return 1
+	}
+	return 0
+}
+
+// mcpMux routes /mcp to h, behind auth if there is any, and serves the
+// protected resource metadata when auth uses OAuth.
+func mcpMux(h *mcpHandler, auth *mcpAuth) *http.ServeMux {
+	mux := http.NewServeMux()
+	if auth == nil {
+		mux.Handle("/mcp", h)
+		return mux
+	}
+	mux.Handle("/mcp", auth.wrap(h))
+	if auth.keys != nil {
+		// Clients of older spec revisions look for the metadata at the
+		// root rather than under the resource's path.
+		mux.HandleFunc("/.well-known/oauth-protected-resource", auth.metadata)
+		if p := auth.metadataPath(); p != "/.well-known/oauth-protected-resource" {
+			mux.HandleFunc(p, auth.metadata)
+		}
+	}
+	return mux
+}
+
+// Most clients never send DELETE, so sessions nobody has used for
+// mcpSessionIdle end on their own, and at most mcpMaxSessions are open at
+// once.
+const (
+	mcpSessionIdle = 30 * time.Minute
+	mcpMaxSessions = 1000
+)
+
+// mcpHandler serves the Streamable HTTP transport of protocol 2025-03-26
+// and later. A client POSTs each JSON-RPC message; a request is answered
+// with a JSON body, or with an event stream that carries its progress
+// before the response when it asks for progress. initialize starts a
+// session, named by the Mcp-Session-Id header on every later message; GET
+// opens the session's stream for messages that belong to no request, and
+// DELETE ends the session. Sessions share one cache, so many clients cost
+// CNN no more requests than one. An expired session is unknown, so its
+// client gets 404 and starts a new one. Not implemented: batches, which
+// 2025-06-18 dropped, and resuming a broken stream with Last-Event-ID.
+type mcpHandler struct {
+	cache       *resultCache
+	origins     map[string]bool
+	idle        time.Duration
+	maxSessions int
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+	done     chan struct{} // closed by closeAll, which stops expireLoop
+	closed   bool
+}
+
+func newMCPHandler(cache *resultCache, origins []string) *mcpHandler {
+	h := &mcpHandler{
+		cache:       cache,
+		origins:     map[string]bool{},
+		idle:        mcpSessionIdle,
+		maxSessions: mcpMaxSessions,
+		sessions:    map[string]*httpSession{},
+		done:        make(chan struct{}),
+	}
+	for _, o := range origins {
+		h.origins[strings.TrimRight(strings.TrimSpace(o), "/")] = true
+	}
+	go h.expireLoop()
+	return h
+}
+
+// httpSession is an MCP session over HTTP. Messages that belong to no
+// request go to the client's GET stream while one is open and are dropped
+// otherwise, as the transport has no way to deliver them later.
+type httpSession struct {
+	*mcpSession
+	principal string // who started the session; see principalFrom
+
+	// Guarded by mcpHandler.mu: the requests and streams being served,
+	// and when the last of them ended.
+	busy     int
+	lastSeen time.Time
+
+	mu     sync.Mutex
+	stream chan []byte // the open GET stream, nil if none
+}
+
+func (s *httpSession) send(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream != nil {
+		select {
+		case s.stream <- data:
+		default:
+			// A client too slow to read loses messages rather than
+			// stalling the session.
+		}
+	}
+	return nil
+}
+
+// sinkFunc adapts a function to messageSink.
+type sinkFunc func(v any) error
+
+func (f sinkFunc) send(v any) error { return f(v) }
+
+func (h *mcpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browsers send Origin; checking it stops a web page from reaching a
+	// server on localhost through DNS rebinding.
+	if !h.allowedOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		h.post(w, r)
+	case http.MethodGet:
+		h.get(w, r)
+	case http.MethodDelete:
+		if s := h.session(w, r); s != nil {
+			h.mu.Lock()
+			delete(h.sessions, r.Header.Get("Mcp-Session-Id"))
+			h.mu.Unlock()
+			h.release(s)
+			s.close()
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// allowedOrigin accepts requests without an Origin, which do not come from
+// a browser, from localhost pages and from the origins given to
+// -allow-origin. The Host header is no help: under DNS rebinding it names
+// the attacker's domain too.
+func (h *mcpHandler) allowedOrigin(origin string) bool {
+	if origin == "" || h.origins[origin] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// session returns the session the request names, or answers the request
+// with an error and returns nil. The session does not expire until the
+// caller releases it.
+func (h *mcpHandler) session(w http.ResponseWriter, r *http.Request) *httpSession {
+	id := r.Header.Get("Mcp-Session-Id")
+	if id == "" {
+		http.Error(w, "missing Mcp-Session-Id header", http.StatusBadRequest)
+		return nil
+	}
+	if v := r.Header.Get("Mcp-Protocol-Version"); v != "" && !protocolVersions[v] {
+		http.Error(w, "unsupported MCP-Protocol-Version "+v, http.StatusBadRequest)
+		return nil
+	}
+	h.mu.Lock()
+	s := h.sessions[id]
+	if s == nil || s.principal != principalFrom(r.Context()) {
+		h.mu.Unlock()
+		// 404 tells the client to start a new session. Someone else's
+		// session is as good as unknown: knowing its ID is no credential.
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return nil
+	}
+	s.busy++
+	h.mu.Unlock()
+	return s
+}
+
+// release marks the end of a request or stream using s.
+func (h *mcpHandler) release(s *httpSession) {
+	h.mu.Lock()
+	s.busy--
+	s.lastSeen = now()
+	h.mu.Unlock()
+}
+
+// expireLoop ends idle sessions until closeAll.
+func (h *mcpHandler) expireLoop() {
+	tick := time.NewTicker(h.idle / 10)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			h.expire()
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// expire ends the sessions that have served nothing for h.idle. Ending
+// one also stops its subscription poller.
+func (h *mcpHandler) expire() {
+	t := now()
+	var idle []*httpSession
+	h.mu.Lock()
+	for id, s := range h.sessions {
+		if s.busy == 0 && t.Sub(s.lastSeen) >= h.idle {
+			delete(h.sessions, id)
+			idle = append(idle, s)
+		}
+	}
+	h.mu.Unlock()
+	for _, s := range idle {
+		s.close()
+	}
+}
+
+func (h *mcpHandler) post(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 4*1024*1024))
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.As(err, new(*http.MaxBytesError)) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	var req rpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSONStatus(w, http.StatusBadRequest, rpcResponse{JSONRPC: "2.0", Error: &rpcError{-32700, "parse error"}})
+		return
+	}
+
+	if req.Method == "initialize" && r.Header.Get("Mcp-Session-Id") == "" {
+		h.initialize(w, r, req)
+		return
+	}
+	s := h.session(w, r)
+	if s == nil {
+		return
+	}
+	defer h.release(s)
+	if req.Method == "initialize" {
+		writeJSONStatus(w, http.StatusBadRequest, rpcResponse{JSONRPC: "2.0", ID: req.ID,
+			Error: &rpcError{-32600, "the session is already initialized; start a new one without Mcp-Session-Id"}})
+		return
+	}
+	if len(req.ID) == 0 || string(req.ID) == "null" || req.Method == "" {
+		// A notification, or a response to a request we never send.
+		s.receive(req, s, nil)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// A request. Its messages come back through msgs; only the response
+	// fits in a JSON body, so without a stream the others go to the GET
+	// stream. gone drops messages once the client has disconnected, which
+	// does not cancel the request.
+	stream := strings.Contains(r.Header.Get("Accept"), "text/event-stream") && progressFrom(withProgress(r.Context(), s, req.Params)) != nil
+	msgs, done, gone := make(chan any), make(chan struct{}), make(chan struct{})
+	defer close(gone)
+	reply := sinkFunc(func(v any) error {
+		if _, ok := v.(rpcResponse); !ok && !stream {
+			return s.send(v)
+		}
+		select {
+		case msgs <- v:
+		case <-gone:
+		}
+		return nil
+	})
+	s.receive(req, reply, func() { close(done) })
+
+	if stream {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		http.NewResponseController(w).Flush()
+	}
+	for {
+		select {
+		case v := <-msgs:
+			if !stream {
+				writeJSONStatus(w, http.StatusOK, v)
+				return
+			}
+			if writeEvent(w, v) != nil {
+				return
+			}
+			if _, ok := v.(rpcResponse); ok {
+				return
+			}
+		case <-done:
+			// Cancelled: there is no response to send.
+			if !stream {
+				w.WriteHeader(http.StatusAccepted)
+			}
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// initialize starts a session and answers its initialize request.
+func (h *mcpHandler) initialize(w http.ResponseWriter, r *http.Request, req rpcRequest) {
+	if h.full() {
+		h.expire()
+		if h.full() {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "too many sessions", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	id := hex.EncodeToString(key[:])
+
+	s := &httpSession{mcpSession: newMCPSession(h.cache), principal: principalFrom(r.Context())}
+	s.start(s)
+	var resp any
+	s.receive(req, sinkFunc(func(v any) error { resp = v; return nil }), nil)
+
+	h.mu.Lock()
+	s.lastSeen = now()
+	h.sessions[id] = s
+	h.mu.Unlock()
+	w.Header().Set("Mcp-Session-Id", id)
+	writeJSONStatus(w, http.StatusOK, resp)
+}
+
+func (h *mcpHandler) full() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions) >= h.maxSessions
+}
+
+// get streams the messages of a session that belong to no request, such as
+// resource updates, until the client disconnects or the session ends.
+func (h *mcpHandler) get(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "GET opens an event stream; accept text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	s := h.session(w, r)
+	if s == nil {
+		return
+	}
+	defer h.release(s)
+	ch := make(chan []byte, 64)
+	s.mu.Lock()
+	if s.stream != nil {
+		s.mu.Unlock()
+		http.Error(w, "the session already has a stream open", http.StatusConflict)
+		return
+	}
+	s.stream = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.stream = nil
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	rc.Flush()
+
+	// Comments keep proxies from closing a quiet stream.
+	keepalive := time.NewTicker(25 * time.Second)
+	defer keepalive.Stop()
+	for {
+		select {
+		case data := <-ch:
+			if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		}
+		rc.Flush()
+	}
+}
+
+// closeAll ends every session.
+func (h *mcpHandler) closeAll() {
+	h.mu.Lock()
+	sessions := h.sessions
+	h.sessions = map[string]*httpSession{}
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+	h.mu.Unlock()
+	for _, s := range sessions {
+		s.close()
+	}
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeEvent writes v as one server-sent event and flushes it.
+func writeEvent(w http.ResponseWriter, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	return http.NewResponseController(w).Flush()
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestMCPHTTP(t *testing.T) {
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) (cnnfag.Result, error) {
+		fetches.Add(1)
+		return cnnfag.Result{Score: 43.71, Rating: "fear", Timestamp: time.Date(2026, 8, 11, 14, 0, 0, 0, time.UTC)}, nil
+	}
+	h := newMCPHandler(newResultCache(fetch, time.Minute), []string{"https://app.example.com/"})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	defer h.closeAll()
+	srv.Client().Timeout = 10 * time.Second // a hung handler fails the test
+
+	do := func(method, session, body string, header ...string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if session != "" {
+			req.Header.Set("Mcp-Session-Id", session)
+		}
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	read := func(resp *http.Response) string {
+		t.Helper()
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+	initialize := func() string {
+		t.Helper()
+		resp := do("POST", "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+		body := read(resp)
+		if resp.StatusCode != 200 || !strings.Contains(body, `"protocolVersion":"2025-06-18"`) {
+			t.Fatalf("initialize: %d %s", resp.StatusCode, body)
+		}
+		id := resp.Header.Get("Mcp-Session-Id")
+		if len(id) != 32 {
+			t.Fatalf("session id %q", id)
+		}
+		return id
+	}
+	const call = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_fear_and_greed","arguments":{}}}`
+
+	session := initialize()
+	if resp := do("POST", session, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification: status %d", resp.StatusCode)
+	}
+	resp := do("POST", session, call)
+	if body := read(resp); resp.Header.Get("Content-Type") != "application/json" || !strings.Contains(body, `"id":2`) || !strings.Contains(body, "43.71") {
+		t.Errorf("tools/call: %s %s", resp.Header.Get("Content-Type"), body)
+	}
+
+	// A second session shares the cache.
+	other := initialize()
+	if other == session {
+		t.Fatal("sessions share an id")
+	}
+	read(do("POST", other, call))
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times across sessions, want 1", n)
+	}
+
+	// Asking for progress gets an event stream with the progress first.
+	resp = do("POST", session, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_fear_and_greed","arguments":{},"_meta":{"progressToken":"p"}}}`)
+	body := read(resp)
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("progress: content type %q", resp.Header.Get("Content-Type"))
+	}
+	events := strings.Split(body, "\n\n")
+	if len(events) != 2 || !strings.Contains(events[0], "event: message\ndata: ") || !strings.Contains(events[0], "notifications/progress") || !strings.Contains(events[1], `"id":3`) {
+		t.Errorf("progress stream:\n%s", body)
+	}
+
+	// Messages outside a request's response go to the GET stream.
+	stream := do("GET", session, "")
+	if stream.StatusCode != 200 || stream.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("GET: %d %s", stream.StatusCode, stream.Header.Get("Content-Type"))
+	}
+	if resp := do("GET", session, ""); resp.StatusCode != http.StatusConflict {
+		t.Errorf("second GET: status %d", resp.StatusCode)
+	}
+	read(do("POST", session, `{"jsonrpc":"2.0","id":4,"method":"logging/setLevel","params":{"level":"debug"}}`))
+	read(do("POST", session, call))
+	events2 := bufio.NewScanner(stream.Body)
+	var got []string
+	for events2.Scan() && events2.Text() != "" {
+		got = append(got, events2.Text())
+	}
+	if len(got) != 2 || !strings.Contains(got[1], "served from the cache") {
+		t.Errorf("GET stream event: %q", got)
+	}
+
+	for _, tc := range []struct {
+		name           string
+		method, sessID string
+		body           string
+		header         []string
+		status         int
+	}{
+		{"no session", "POST", "", call, nil, http.StatusBadRequest},
+		{"unknown session", "POST", "0123", call, nil, http.StatusNotFound},
+		{"bad version", "POST", session, call, []string{"Mcp-Protocol-Version", "1999-01-01"}, http.StatusBadRequest},
+		{"batch", "POST", session, "[" + call + "]", nil, http.StatusBadRequest},
+		{"too large", "POST", session, strings.Repeat(" ", 4<<20) + call, nil, http.StatusRequestEntityTooLarge},
+		{"initialize again", "POST", session, `{"jsonrpc":"2.0","id":9,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`, nil, http.StatusBadRequest},
+		{"foreign origin", "POST", session, call, []string{"Origin", "http://evil.example"}, http.StatusForbidden},
+		{"localhost origin", "POST", session, call, []string{"Origin", "http://localhost:6274"}, http.StatusOK},
+		{"allowed origin", "POST", session, call, []string{"Origin", "https://app.example.com"}, http.StatusOK},
+		{"GET without event stream", "GET", session, "", []string{"Accept", "application/json"}, http.StatusNotAcceptable},
+		{"PUT", "PUT", session, call, nil, http.StatusMethodNotAllowed},
+	} {
+		if resp := do(tc.method, tc.sessID, tc.body, tc.header...); resp.StatusCode != tc.status {
+			t.Errorf("%s: status %d, want %d: %s", tc.name, resp.StatusCode, tc.status, read(resp))
+		} else {
+			read(resp)
+		}
+	}
+
+	// A body that fails to read for any other reason is a bad request.
+	req := httptest.NewRequest("POST", "/", iotest.ErrReader(errors.New("connection reset")))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Session-Id", session)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unreadable body: status %d, want 400", rec.Code)
+	}
+
+	// DELETE ends the session and its stream.
+	if resp := do("DELETE", session, ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE: status %d", resp.StatusCode)
+	}
+	// The table's calls logged more to the stream; it must now end.
+	if _, err := io.ReadAll(stream.Body); err != nil {
+		t.Errorf("stream after DELETE: %v", err)
+	}
+	stream.Body.Close()
+	if resp := do("POST", session, call); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("after DELETE: status %d", resp.StatusCode)
+	}
+	if resp := do("POST", other, call); resp.StatusCode != http.StatusOK {
+		t.Errorf("other session after DELETE: status %d", resp.StatusCode)
+	}
+}
+
+func TestMCPHTTPSessionLimits(t *testing.T) {
+	var offset atomic.Int64
+	now = func() time.Time { return time.Now().Add(time.Duration(offset.Load())) }
+	defer func() { now = time.Now }()
+
+	fetch := func(ctx context.Context) (cnnfag.Result, error) { return cnnfag.Result{Score: 43.71}, nil }
+	h := newMCPHandler(newResultCache(fetch, time.Minute), nil)
+	h.maxSessions = 2
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	defer h.closeAll()
+	srv.Client().Timeout = 10 * time.Second
+
+	do := func(method, session, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if session != "" {
+			req.Header.Set("Mcp-Session-Id", session)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method != "GET" {
+			resp.Body.Close()
+		}
+		return resp
+	}
+	const initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`
+	const ping = `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+
+	idle := do("POST", "", initialize).Header.Get("Mcp-Session-Id")
+	streaming := do("POST", "", initialize).Header.Get("Mcp-Session-Id")
+	if resp := do("POST", "", initialize); resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("initialize past the cap: status %d", resp.StatusCode)
+	}
+	h.mu.Lock()
+	idleSession := h.sessions[idle]
+	h.mu.Unlock()
+
+	// An open stream keeps its session alive; the other expires, and its
+	// client is told to start again.
+	stream := do("GET", streaming, "")
+	defer stream.Body.Close()
+	offset.Store(int64(mcpSessionIdle - time.Minute))
+	h.expire()
+	if resp := do("POST", idle, ping); resp.StatusCode != http.StatusOK {
+		t.Errorf("session used within the idle time: status %d", resp.StatusCode)
+	}
+	offset.Store(int64(2*mcpSessionIdle + time.Minute))
+	h.expire()
+	if resp := do("POST", idle, ping); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expired session: status %d, want 404", resp.StatusCode)
+	}
+	select {
+	case <-idleSession.ctx.Done():
+	default:
+		t.Error("the expired session was not closed")
+	}
+	if resp := do("POST", streaming, ping); resp.StatusCode != http.StatusOK {
+		t.Errorf("streaming session: status %d", resp.StatusCode)
+	}
+
+	// Expiring sessions makes room under the cap.
+	if resp := do("POST", "", initialize); resp.StatusCode != http.StatusOK {
+		t.Errorf("initialize after an expiry: status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Prompts are templates a client offers its user, such as slash commands.
+// Each embeds the data it is about as fng:// resources, fetched when the
+// prompt is got, so the model starts from the same numbers the user sees.
+
+type promptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type prompt struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Arguments   []promptArgument `json:"arguments,omitempty"`
+
+	// validate, if set, checks the arguments before anything is fetched.
+	validate func(args map[string]string) *rpcError                                       `json:"-"`
+	messages func(res cnnfag.Result, args map[string]string) ([]promptMessage, *rpcError) `json:"-"`
+}
+
+type promptMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type embeddedResource struct {
+	Type     string          `json:"type"`
+	Resource resourceContent `json:"resource"`
+}
+
+var prompts = []prompt{
+	{
+		Name:        "daily_brief",
+		Title:       "Daily sentiment brief",
+		Description: "A short brief on US stock market sentiment from CNN's Fear & Greed Index on a day.",
+		Arguments: []promptArgument{
+			{Name: "date", Description: "Day to brief on, YYYY-MM-DD, within about the past year. The latest reading by default."},
+		},
+		validate: validateBriefDate,
+		messages: dailyBrief,
+	},
+	{
+		Name:        "explain_move",
+		Title:       "Explain today's move",
+		Description: "Explains the latest change in CNN's Fear & Greed Index through its seven indicators.",
+		messages:    explainMove,
+	},
+	{
+		Name:        "contrarian_check",
+		Title:       "Contrarian check",
+		Description: "Weighs whether market-wide sentiment argues for a contrarian view on a ticker.",
+		Arguments: []promptArgument{
+			{Name: "ticker", Description: "Ticker symbol, such as SPY or AAPL.", Required: true},
+		},
+		validate: validateTicker,
+		messages: contrarianCheck,
+	},
+}
+
+func getPrompt(ctx context.Context, params json.RawMessage, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
+	var p struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{-32602, "invalid params"}
+	}
+	var pr *prompt
+	for i := range prompts {
+		if prompts[i].Name == p.Name {
+			pr = &prompts[i]
+		}
+	}
+	if pr == nil {
+		return nil, &rpcError{-32602, "unknown prompt: " + p.Name}
+	}
+	known := map[string]bool{}
+	for _, a := range pr.Arguments {
+		known[a.Name] = true
+		if a.Required && p.Arguments[a.Name] == "" {
+			return nil, &rpcError{-32602, "missing required argument " + a.Name}
+		}
+	}
+	for name := range p.Arguments {
+		if !known[name] {
+			return nil, &rpcError{-32602, "unknown argument " + name}
+		}
+	}
+	if pr.validate != nil {
+		if rerr := pr.validate(p.Arguments); rerr != nil {
+			return nil, rerr
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	res, err := fetch(ctx)
+	if err != nil {
+		return nil, &rpcError{-32603, err.Error()}
+	}
+	msgs, rerr := pr.messages(res, p.Arguments)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return map[string]any{"description": pr.Description, "messages": msgs}, nil
+}
+
+func userText(text string) promptMessage {
+	return promptMessage{Role: "user", Content: textContent{Type: "text", Text: text}}
+}
+
+// userResource embeds the JSON of the resource at uri.
+func userResource(uri string, res cnnfag.Result) (promptMessage, *rpcError) {
+	contents, rerr := resourceContents(uri, res)
+	if rerr != nil {
+		return promptMessage{}, rerr
+	}
+	return promptMessage{Role: "user", Content: embeddedResource{Type: "resource", Resource: contents[0]}}, nil
+}
+
+func validateBriefDate(args map[string]string) *rpcError {
+	if _, err := time.Parse(time.DateOnly, args["date"]); args["date"] != "" && err != nil {
+		return &rpcError{-32602, "date must be a date like 2006-01-02"}
+	}
+	return nil
+}
+
+func dailyBrief(res cnnfag.Result, args map[string]string) ([]promptMessage, *rpcError) {
+	instructions := "Write a short brief, under 200 words, on the mood of the US stock market %s from CNN's Fear & Greed Index data below. " +
+		"Give the reading and its rating, compare it with the earlier readings provided, name the indicators leaning furthest toward fear or greed, " +
+		"and say what the picture suggests. Stick to the data; do not predict prices."
+
+	if args["date"] == "" {
+		msg, _ := userResource(currentURI, res)
+		return []promptMessage{userText(fmt.Sprintf(instructions, "today")), msg}, nil
+	}
+
+	date, _ := time.Parse(time.DateOnly, args["date"]) // checked by validateBriefDate
+	msgs := []promptMessage{userText(fmt.Sprintf(instructions, "on "+args["date"]))}
+	msg, rerr := userResource("fng://history/"+args["date"], res)
+	if rerr != nil {
+		return nil, rerr
+	}
+	msgs = append(msgs, msg)
+	// The same day a week and a month before, for the comparison; skipped
+	// when they fall before the history.
+	for _, earlier := range []time.Time{date.AddDate(0, 0, -7), date.AddDate(0, -1, 0)} {
+		if msg, rerr := userResource("fng://history/"+earlier.Format(time.DateOnly), res); rerr == nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, nil
+}
+
+func explainMove(res cnnfag.Result, _ map[string]string) ([]promptMessage, *rpcError) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CNN's Fear & Greed Index is at %.0f (%s), %+.1f points from the previous close of %.0f. ",
+		res.Score, res.Rating, res.Score-res.PreviousClose, res.PreviousClose)
+	b.WriteString("Explain what drove the move, indicator by indicator, from the latest day's change in each indicator's raw value below " +
+		"and the full data after it. Say which indicators pushed toward fear and which toward greed, and which mattered most. " +
+		"Keep it short and plain.\n\n")
+
+	rows := make([][]string, 0, len(cnnfag.IndicatorIDs))
+	for _, id := range cnnfag.IndicatorIDs {
+		ind, _ := res.Indicator(id)
+		latest, change := "", ""
+		if n := len(ind.History); n > 0 {
+			latest = formatRaw(ind.History[n-1].Value)
+			if n > 1 {
+				change = formatChange(ind.History[n-1].Value - ind.History[n-2].Value)
+			}
+		}
+		rows = append(rows, []string{indicatorInfo[id].name, fmt.Sprintf("%.0f", ind.Score), ind.Rating, latest, indicatorInfo[id].unit, change})
+	}
+	writeAligned(&b, []string{"INDICATOR", "SCORE", "RATING", "LATEST", "UNIT", "CHANGE"}, rows, func(_, _ int, cell string) string { return cell })
+
+	msg, _ := userResource(currentURI, res)
+	return []promptMessage{userText(b.String()), msg}, nil
+}
+
+// tickerPattern accepts the symbols of the common exchanges and indexes,
+// and keeps the argument from carrying instructions into the prompt.
+var tickerPattern = regexp.MustCompile(`^[A-Za-z0-9.^=-]{1,15}$`)
+
+func validateTicker(args map[string]string) *rpcError {
+	if !tickerPattern.MatchString(args["ticker"]) {
+		return &rpcError{-32602, "ticker must be a symbol such as SPY or BRK.B"}
+	}
+	return nil
+}
+
+func contrarianCheck(res cnnfag.Result, args map[string]string) ([]promptMessage, *rpcError) {
+	ticker := strings.ToUpper(args["ticker"])
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Run a contrarian check on %s. Contrarians lean against the crowd: extreme fear can mark a buying opportunity "+
+		"and extreme greed a time for caution. Using CNN's Fear & Greed Index data below, judge how stretched US market "+
+		"sentiment is now and what a contrarian would make of it for %s. The index measures the whole US market, not %s; "+
+		"say what you would need to know about %s itself to go further. This is not investment advice; say so briefly.\n", ticker, ticker, ticker, ticker)
+	if index, _ := res.Series("index"); len(index) > 0 {
+		s := cnnfag.Summarize(index)
+		fmt.Fprintf(&b, "\nOver the %d trading days since %s the index averaged %.0f (median %.0f), ranging from %.0f on %s to %.0f on %s. "+
+			"%.0f%% of those days closed at or below today's reading; %d were in extreme fear and %d in extreme greed.\n",
+			s.Count, s.Start.Format(time.DateOnly), s.Mean, s.Median, s.Min.Value, s.Min.Date.Format(time.DateOnly),
+			s.Max.Value, s.Max.Date.Format(time.DateOnly), s.Percentile, s.BandDays["extreme fear"], s.BandDays["extreme greed"])
+	}
+
+	msg, _ := userResource(currentURI, res)
+	return []promptMessage{userText(b.String()), msg}, nil
+}
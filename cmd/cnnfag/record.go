@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// runRecord fetches the index once through a cnnfag.Recorder, so every raw
+// response, a failed one included, lands in the fixture directory. Run it
+// from cron to build up a history of real payloads.
+func runRecord(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag record", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", "recordings", "directory to save the raw responses in")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	old := cnnfag.HTTPClient
+	client := *old
+	client.Transport = &cnnfag.Recorder{Dir: *dir, Transport: old.Transport}
+	cnnfag.HTTPClient = &client
+	defer func() { cnnfag.HTTPClient = old }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag record:", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "recorded %.0f (%s) as of %s in %s\n", res.Score, res.Rating, res.Timestamp.Format(time.RFC3339), *dir)
+	return 0
+}
+
+// replayClient returns the HTTP client behind -replay and -as-of. Without a
+// date it plays the recordings back in order, one per request; with one it
+// answers every request with the latest recording made by then, so print,
+// -json and mcp all see the index as it was known then. A date without a
+// time means the end of that day in UTC, after the day's close.
+func replayClient(dir, asOf string) (*http.Client, error) {
+	if dir == "" {
+		return nil, errors.New("-as-of needs -replay")
+	}
+	if asOf == "" {
+		replayer, err := cnnfag.NewReplayer(dir)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Client{Transport: replayer}, nil
+	}
+
+	at, err := time.Parse(time.DateOnly, asOf)
+	if err == nil {
+		at = at.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	} else {
+		if at, err = time.Parse(time.RFC3339, asOf); err != nil {
+			return nil, fmt.Errorf("-as-of %q is neither 2006-01-02 nor RFC 3339", asOf)
+		}
+	}
+	replayer, err := cnnfag.NewReplayerAt(dir, at)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: replayer}, nil
+}
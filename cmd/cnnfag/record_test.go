@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunRecord(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	dir := t.TempDir()
+	var stdout, stderr strings.Builder
+	if code := run([]string{"record", "-dir", dir}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(record) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "recorded 64 (greed)") {
+		t.Errorf("record output: %q", stdout.String())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.http"))
+	if len(files) != 1 {
+		t.Fatalf("recordings: %v, want one", files)
+	}
+	saved, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), " 200 OK") || !strings.Contains(string(saved), `"previous_1_year"`) {
+		t.Errorf("recording does not hold the raw response:\n%.200s", saved)
+	}
+}
+
+func TestRunReplay(t *testing.T) {
+	dir := t.TempDir()
+	for _, day := range []int{1, 15} {
+		end := time.Date(2026, 5, day, 20, 0, 0, 0, time.UTC)
+		body := cnnfag.Synthetic(cnnfag.SyntheticOptions{Seed: 1, Days: 5, End: end})
+		raw := "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n" + string(body)
+		name := end.Format("20060102T150405.000000000Z") + ".http"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(raw), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Any network access fails the test.
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: errorTransport{}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	var stdout, stderr strings.Builder
+	if code := run([]string{"-replay", dir, "-as-of", "2026-05-03"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(-replay -as-of) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "as of 2026-05-01T20:00:00Z") {
+		t.Errorf("replayed output: %q", stdout.String())
+	}
+
+	// The mcp subcommand sees the same snapshot.
+	stdout.Reset()
+	in := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_fear_and_greed","arguments":{}}}` + "\n"
+	if code := run([]string{"-replay", dir, "-as-of", "2026-05-15T20:00:00Z", "mcp"}, strings.NewReader(in), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(-replay mcp) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "2026-05-15T20:00:00Z") {
+		t.Errorf("replayed mcp output: %q", stdout.String())
+	}
+
+	// A date covers the whole day, but not a day later.
+	for asOf, want := range map[string]string{"2026-05-15": "as of 2026-05-15", "2026-05-14": "as of 2026-05-01"} {
+		stdout.Reset()
+		if code := run([]string{"-replay", dir, "-as-of", asOf}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("run(-as-of %s) = %d, stderr: %s", asOf, code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("-as-of %s: %q, want %q", asOf, stdout.String(), want)
+		}
+	}
+
+	// Without a date the recordings play in order.
+	stdout.Reset()
+	if code := run([]string{"-replay", dir}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(-replay) = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "as of 2026-05-01") {
+		t.Errorf("first replayed output: %q", stdout.String())
+	}
+
+	if cnnfag.HTTPClient.Transport != (errorTransport{}) {
+		t.Error("run did not restore cnnfag.HTTPClient")
+	}
+	for _, args := range [][]string{
+		{"-as-of", "2026-05-03"},
+		{"-replay", dir, "-as-of", "May 3rd"},
+		{"-replay", dir, "-as-of", "2026-04-30"},
+		{"-replay", t.TempDir()},
+	} {
+		if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+	"github.com/wildsurfer/cnn-fear-and-greed-parse/v2/render"
+)
+
+// runRender writes a PNG of the gauge, the history chart or the indicator
+// small multiples, for email and chat posts.
+func runRender(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("out", "", "write the PNG to `path`, or - for stdout")
+	kind := fs.String("kind", "gauge", "image to draw: gauge, chart or indicators")
+	indicator := fs.String("indicator", "index", "with -kind chart, the series: index or one of "+strings.Join(cnnfag.IndicatorIDs, ", "))
+	width := fs.Int("width", 0, "image width in pixels (default 600 for gauge, 800 for chart, 1200 for indicators)")
+	height := fs.Int("height", 0, "image height in pixels (default 400 for chart, 600 for indicators; the gauge's follows its width)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	sizes := map[string][2]int{"gauge": {600, 0}, "chart": {800, 400}, "indicators": {1200, 600}}
+	size, ok := sizes[*kind]
+	switch {
+	case !ok:
+		fmt.Fprintf(stderr, "cnnfag render: unknown kind %q\n", *kind)
+		return 2
+	case *out == "":
+		fmt.Fprintln(stderr, "cnnfag render: -out is required")
+		return 2
+	case !validSeries(*indicator):
+		fmt.Fprintf(stderr, "cnnfag render: unknown indicator %q\n", *indicator)
+		return 2
+	case *width < 0 || *height < 0 || *width > 10000 || *height > 10000:
+		fmt.Fprintln(stderr, "cnnfag render: -width and -height must be between 1 and 10000")
+		return 2
+	}
+	if *width == 0 {
+		*width = size[0]
+	}
+	if *height == 0 {
+		*height = size[1]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag render:", err)
+		return 1
+	}
+
+	var img image.Image
+	switch *kind {
+	case "gauge":
+		img = render.Gauge(res, *width)
+	case "chart":
+		series, _ := res.Series(*indicator)
+		img = render.Chart(series, *width, *height, *indicator == "index")
+	case "indicators":
+		img = render.Indicators(res, *width, *height)
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error { return png.Encode(w, img) })
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag render:", err)
+		return 1
+	}
+	return 0
+}
+
+// writeOutput calls write with the file at path, or with stdout if path is
+// "-".
+func writeOutput(path string, stdout io.Writer, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunRender(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		args          []string
+		width, height int
+	}{
+		{[]string{"-out", "gauge.png"}, 600, 0},
+		{[]string{"-kind", "chart", "-indicator", "vix", "-width", "320", "-out", "chart.png"}, 320, 400},
+		{[]string{"-kind", "indicators", "-out", "indicators.png"}, 1200, 600},
+	} {
+		for i, a := range tc.args {
+			if strings.HasSuffix(a, ".png") {
+				tc.args[i] = filepath.Join(dir, a)
+			}
+		}
+		var stdout, stderr strings.Builder
+		if code := run(append([]string{"render"}, tc.args...), strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("run(render %v) = %d, stderr: %s", tc.args, code, stderr.String())
+		}
+		f, err := os.Open(tc.args[len(tc.args)-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("render %v: %v", tc.args, err)
+		}
+		if b := img.Bounds(); b.Dx() != tc.width || (tc.height != 0 && b.Dy() != tc.height) {
+			t.Errorf("render %v is %v, want %dx%d", tc.args, b, tc.width, tc.height)
+		}
+	}
+
+	// - writes to stdout.
+	var stdout bytes.Buffer
+	if code := run([]string{"render", "-out", "-", "-width", "100"}, strings.NewReader(""), &stdout, new(strings.Builder)); code != 0 {
+		t.Fatalf("run(render -out -) = %d", code)
+	}
+	if _, err := png.Decode(&stdout); err != nil {
+		t.Errorf("stdout is not a PNG: %v", err)
+	}
+
+	for _, args := range [][]string{{"render"}, {"render", "-kind", "pie", "-out", "x.png"}, {"render", "-indicator", "gold", "-out", "x.png"}} {
+		if code := run(args, strings.NewReader(""), new(strings.Builder), new(strings.Builder)); code != 2 {
+			t.Errorf("run(%v) = %d, want 2", args, code)
+		}
+	}
+}
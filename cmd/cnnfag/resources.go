@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Resources let clients attach the index as context instead of calling a
+// tool. Every read returns two contents for the same URI: the data as JSON
+// and a short plain-text rendering a person, or a model, can read at a
+// glance.
+
+const currentURI = "fng://current"
+
+// resourceNotFound is the error code the MCP spec gives unknown resources.
+const resourceNotFound = -32002
+
+var resourceTemplates = []map[string]any{
+	{
+		"uriTemplate": "fng://indicators/{id}",
+		"name":        "indicator",
+		"title":       "Fear & Greed indicator",
+		"description": "One of the seven component indicators by ID (" + strings.Join(cnnfag.IndicatorIDs, ", ") + "): score, rating and about a year of daily raw values.",
+		"mimeType":    "application/json",
+	},
+	{
+		"uriTemplate": "fng://history/{date}",
+		"name":        "history",
+		"title":       "Fear & Greed on a date",
+		"description": "The index and the seven indicators on a date, YYYY-MM-DD, within about the past year. On a day the market was closed, the previous trading day's values.",
+		"mimeType":    "application/json",
+	},
+}
+
+// resourceList lists the current index and, as concrete instances of the
+// indicator template, the seven indicators.
+func resourceList() []map[string]any {
+	list := []map[string]any{{
+		"uri":         currentURI,
+		"name":        "current",
+		"title":       "Fear & Greed Index now",
+		"description": "CNN's Fear & Greed index: score, rating, past-period values and the seven indicators' scores.",
+		"mimeType":    "application/json",
+	}}
+	for _, id := range cnnfag.IndicatorIDs {
+		list = append(list, map[string]any{
+			"uri":         "fng://indicators/" + id,
+			"name":        id,
+			"title":       indicatorInfo[id].name,
+			"description": indicatorInfo[id].name + " indicator, with daily " + indicatorInfo[id].unit + " values.",
+			"mimeType":    "application/json",
+		})
+	}
+	return list
+}
+
+type resourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// indicatorResource is the JSON of fng://indicators/{id}.
+type indicatorResource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+	cnnfag.Indicator
+}
+
+// dayResource is the JSON of fng://history/{date}.
+type dayResource struct {
+	Date       string                  `json:"date"`
+	Index      cnnfag.Value            `json:"index"`
+	Indicators map[string]cnnfag.Value `json:"indicators"`
+}
+
+func readResource(ctx context.Context, params json.RawMessage, fetch func(context.Context) (cnnfag.Result, error)) (any, *rpcError) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.URI == "" {
+		return nil, &rpcError{-32602, "invalid params"}
+	}
+
+	// Check the URI before fetching, so a typo does not cost a request.
+	if _, _, rerr := parseResourceURI(p.URI); rerr != nil {
+		return nil, rerr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	res, err := fetch(ctx)
+	if err != nil {
+		// Resources have no error result like tools; the client reports
+		// the JSON-RPC error instead.
+		return nil, &rpcError{-32603, err.Error()}
+	}
+
+	contents, rerr := resourceContents(p.URI, res)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return map[string]any{"contents": contents}, nil
+}
+
+// resourceContents renders the resource at uri, which must parse, from res:
+// the JSON first, then the text.
+func resourceContents(uri string, res cnnfag.Result) ([]resourceContent, *rpcError) {
+	id, date, rerr := parseResourceURI(uri)
+	if rerr != nil {
+		return nil, rerr
+	}
+	var data any
+	var text string
+	switch {
+	case id != "":
+		ind, _ := res.Indicator(id)
+		data = indicatorResource{ID: id, Name: indicatorInfo[id].name, Unit: indicatorInfo[id].unit, Indicator: ind}
+		text = indicatorText(id, ind)
+	case !date.IsZero():
+		day, ok := dayAt(res, date)
+		if !ok {
+			return nil, &rpcError{resourceNotFound, "no values on or before " + date.Format(time.DateOnly)}
+		}
+		data, text = day, dayText(day)
+	default:
+		data, text = withoutHistory(res), currentText(res)
+	}
+
+	js, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, &rpcError{-32603, err.Error()}
+	}
+	return []resourceContent{
+		{URI: uri, MimeType: "application/json", Text: string(js)},
+		{URI: uri, MimeType: "text/plain", Text: text},
+	}, nil
+}
+
+// parseResourceURI checks uri against the resources and templates, and
+// returns the indicator ID or the date it names, if any.
+func parseResourceURI(uri string) (id string, date time.Time, rerr *rpcError) {
+	switch rest, _ := strings.CutPrefix(uri, "fng://"); {
+	case uri == currentURI:
+	case strings.HasPrefix(rest, "indicators/"):
+		id = strings.TrimPrefix(rest, "indicators/")
+		if _, ok := (cnnfag.Result{}).Indicator(id); !ok {
+			return "", date, &rpcError{resourceNotFound, "resource not found: " + uri}
+		}
+	case strings.HasPrefix(rest, "history/"):
+		var err error
+		if date, err = time.Parse(time.DateOnly, strings.TrimPrefix(rest, "history/")); err != nil {
+			return "", date, &rpcError{-32602, "invalid date in " + uri + ", want fng://history/YYYY-MM-DD"}
+		}
+	default:
+		return "", date, &rpcError{resourceNotFound, "resource not found: " + uri}
+	}
+	return id, date, nil
+}
+
+// dayAt collects the index and indicator values on date, falling back to
+// the previous trading day. It reports false before the index history.
+func dayAt(res cnnfag.Result, date time.Time) (dayResource, bool) {
+	index, _ := res.Series("index")
+	v, ok := cnnfag.At(index, date)
+	if !ok {
+		return dayResource{}, false
+	}
+	day := dayResource{Date: date.Format(time.DateOnly), Index: v, Indicators: map[string]cnnfag.Value{}}
+	for _, id := range cnnfag.IndicatorIDs {
+		series, _ := res.Series(id)
+		if v, ok := cnnfag.At(series, date); ok {
+			day.Indicators[id] = v
+		}
+	}
+	return day, true
+}
+
+func currentText(res cnnfag.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Fear & Greed Index %.0f (%s) as of %s\n", res.Score, res.Rating, res.Timestamp.Format(time.RFC3339))
+	fmt.Fprintf(&b, "previous close %.0f · week ago %.0f · month ago %.0f · year ago %.0f\n\n",
+		res.PreviousClose, res.OneWeekAgo, res.OneMonthAgo, res.OneYearAgo)
+	rows := make([][]string, 0, len(cnnfag.IndicatorIDs))
+	for _, id := range cnnfag.IndicatorIDs {
+		ind, _ := res.Indicator(id)
+		rows = append(rows, []string{indicatorInfo[id].name, fmt.Sprintf("%.0f", ind.Score), ind.Rating})
+	}
+	writeAligned(&b, []string{"INDICATOR", "SCORE", "RATING"}, rows, func(_, _ int, cell string) string { return cell })
+	return b.String()
+}
+
+func indicatorText(id string, ind cnnfag.Indicator) string {
+	info := indicatorInfo[id]
+	text := fmt.Sprintf("%s: score %.0f (%s) as of %s\n", info.name, ind.Score, ind.Rating, ind.Timestamp.Format(time.RFC3339))
+	if n := len(ind.History); n > 0 {
+		latest := ind.History[n-1]
+		text += fmt.Sprintf("latest %s %s on %s", info.unit, formatRaw(latest.Value), latest.Date.Format(time.DateOnly))
+		if n > 1 {
+			text += ", " + formatChange(latest.Value-ind.History[n-2].Value) + " on the day"
+		}
+		s := cnnfag.Summarize(ind.History)
+		text += fmt.Sprintf("\n%d days from %s: low %s, high %s, median %s\n", s.Count, s.Start.Format(time.DateOnly),
+			formatRaw(s.Min.Value), formatRaw(s.Max.Value), formatRaw(s.Median))
+	}
+	return text
+}
+
+func dayText(day dayResource) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Fear & Greed Index %.0f (%s) on %s\n\n", day.Index.Value, day.Index.Rating, day.Index.Date.Format(time.DateOnly))
+	rows := make([][]string, 0, len(day.Indicators))
+	for _, id := range cnnfag.IndicatorIDs {
+		if v, ok := day.Indicators[id]; ok {
+			rows = append(rows, []string{indicatorInfo[id].name, formatRaw(v.Value), indicatorInfo[id].unit, v.Rating})
+		}
+	}
+	writeAligned(&b, []string{"INDICATOR", "VALUE", "UNIT", "RATING"}, rows, func(_, _ int, cell string) string { return cell })
+	return b.String()
+}
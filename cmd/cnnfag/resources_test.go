@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestResources(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	fetches := 0
+	fetch := func(context.Context) (cnnfag.Result, error) {
+		fetches++
+		return cnnfag.Result{
+			Score:     40,
+			Rating:    "fear",
+			Timestamp: day(10).Add(14 * time.Hour),
+			History: []cnnfag.Point{
+				{Date: day(6), Score: 20, Rating: "extreme fear"},
+				{Date: day(9), Score: 50, Rating: "neutral"},
+				{Date: day(10).Add(14 * time.Hour), Score: 40, Rating: "fear"},
+			},
+			MarketVolatility: cnnfag.Indicator{Score: 55, Rating: "greed", History: []cnnfag.Value{
+				{Date: day(9), Value: 18.25, Rating: "greed"},
+				{Date: day(10).Add(14 * time.Hour), Value: 17.5, Rating: "greed"},
+			}},
+		}, nil
+	}
+
+	if list := resourceList(); len(list) != 8 || list[0]["uri"] != currentURI || list[5]["uri"] != "fng://indicators/vix" {
+		t.Errorf("resourceList = %v", list)
+	}
+
+	read := func(uri string) ([]resourceContent, *rpcError) {
+		t.Helper()
+		params, _ := json.Marshal(map[string]string{"uri": uri})
+		out, rerr := readResource(context.Background(), params, fetch)
+		if rerr != nil {
+			return nil, rerr
+		}
+		return out.(map[string]any)["contents"].([]resourceContent), nil
+	}
+
+	// Every resource comes as JSON and as text, under the URI asked for.
+	for uri, want := range map[string][2]string{
+		currentURI:                 {`"score": 40`, "Fear & Greed Index 40 (fear)"},
+		"fng://indicators/vix":     {`"unit": "VIX"`, "latest VIX 17.50 on 2026-03-10, -0.750 on the day"},
+		"fng://history/2026-03-09": {`"value": 18.25`, "Fear & Greed Index 50 (neutral) on 2026-03-09"},
+		"fng://history/2026-03-08": {`"date": "2026-03-06T00:00:00Z"`, "Fear & Greed Index 20 (extreme fear) on 2026-03-06"},
+	} {
+		contents, rerr := read(uri)
+		if rerr != nil || len(contents) != 2 {
+			t.Errorf("read %s = %v, %v", uri, contents, rerr)
+			continue
+		}
+		if c := contents[0]; c.URI != uri || c.MimeType != "application/json" || !strings.Contains(c.Text, want[0]) {
+			t.Errorf("read %s JSON = %+v, want %s", uri, c, want[0])
+		}
+		if c := contents[1]; c.URI != uri || c.MimeType != "text/plain" || !strings.Contains(c.Text, want[1]) {
+			t.Errorf("read %s text = %+v, want %s", uri, c, want[1])
+		}
+	}
+	if contents, _ := read(currentURI); strings.Contains(contents[0].Text, "history") {
+		t.Errorf("fng://current carries history: %s", contents[0].Text)
+	}
+
+	// Bad URIs fail before fetching.
+	fetches = 0
+	for uri, code := range map[string]int{
+		"fng://indicators/dow":    resourceNotFound,
+		"fng://elsewhere":         resourceNotFound,
+		"https://example.com":     resourceNotFound,
+		"fng://history/yesterday": -32602,
+	} {
+		if _, rerr := read(uri); rerr == nil || rerr.Code != code {
+			t.Errorf("read %s = %v, want code %d", uri, rerr, code)
+		}
+	}
+	if fetches != 0 {
+		t.Errorf("bad URIs fetched %d times", fetches)
+	}
+	if _, rerr := read("fng://history/2025-01-01"); rerr == nil || rerr.Code != resourceNotFound {
+		t.Errorf("read before the history = %v", rerr)
+	}
+
+	fetch = func(context.Context) (cnnfag.Result, error) { return cnnfag.Result{}, errors.New("boom") }
+	if _, rerr := read(currentURI); rerr == nil || rerr.Message != "boom" {
+		t.Errorf("read on a fetch error = %v", rerr)
+	}
+}
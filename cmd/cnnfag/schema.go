@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// validateArgs checks tool arguments against the tool's input schema. It
+// knows the subset of JSON Schema the tool definitions use: an object of
+// string and boolean properties, required names, no extra properties,
+// string enums and the "date" format.
+func validateArgs(schema map[string]any, raw json.RawMessage) error {
+	args := map[string]json.RawMessage{}
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return errors.New("arguments must be an object")
+		}
+	}
+
+	required, _ := schema["required"].([]string)
+	for _, name := range required {
+		if _, ok := args[name]; !ok {
+			return fmt.Errorf("missing required argument %q", name)
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop, ok := props[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("unknown argument %q", name)
+		}
+		if err := validateValue(prop, args[name]); err != nil {
+			return fmt.Errorf("argument %q %w", name, err)
+		}
+	}
+	return nil
+}
+
+func validateValue(prop map[string]any, raw json.RawMessage) error {
+	switch prop["type"] {
+	case "boolean":
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return errors.New("must be a boolean")
+		}
+	case "string":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return errors.New("must be a string")
+		}
+		if enum, ok := prop["enum"].([]string); ok {
+			found := false
+			for _, e := range enum {
+				found = found || e == s
+			}
+			if !found {
+				return fmt.Errorf("must be one of %q", enum)
+			}
+		}
+		if prop["format"] == "date" {
+			if _, err := time.Parse(time.DateOnly, s); err != nil {
+				return errors.New("must be a date like 2006-01-02")
+			}
+		}
+	}
+	return nil
+}
+
+// schemaOf derives the JSON Schema of a type's encoding/json output, for tool
+// output schemas that cannot drift from the structs they describe. Fields
+// are required unless tagged omitempty or pointers; embedded structs are
+// flattened as encoding/json does. It covers the kinds the tool results use.
+func schemaOf(t reflect.Type) map[string]any {
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return schemaOf(t.Elem())
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		required := []string{}
+		addFields(t, props, &required)
+		sort.Strings(required)
+		return map[string]any{"type": "object", "properties": props, "required": required}
+	}
+	panic("schemaOf: unsupported type " + t.String())
+}
+
+func addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addFields(f.Type, props, required)
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = schemaOf(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateArgs(t *testing.T) {
+	schema := historyToolDef["inputSchema"].(map[string]any)
+	for _, tt := range []struct {
+		args string
+		ok   bool
+	}{
+		{`{"series":"index","start":"2025-08-01"}`, true},
+		{`{"series":"vix","start":"2025-08-01","end":"2025-09-01","resample":"weekly"}`, true},
+		{``, false},
+		{`[]`, false},
+		{`{"start":"2025-08-01"}`, false},
+		{`{"series":"vix"}`, false},
+		{`{"series":"dow","start":"2025-08-01"}`, false},
+		{`{"series":"vix","start":"2025-8-1"}`, false},
+		{`{"series":"vix","start":20250801}`, false},
+		{`{"series":"vix","start":"2025-08-01","resample":"daily"}`, false},
+		{`{"series":"vix","start":"2025-08-01","extra":true}`, false},
+	} {
+		err := validateArgs(schema, json.RawMessage(tt.args))
+		if (err == nil) != tt.ok {
+			t.Errorf("validateArgs(%s) = %v, want ok %v", tt.args, err, tt.ok)
+		}
+	}
+
+	flags := toolDef["inputSchema"].(map[string]any)
+	if err := validateArgs(flags, nil); err != nil {
+		t.Errorf("no arguments: %v", err)
+	}
+	if err := validateArgs(flags, json.RawMessage(`{"include_history":1}`)); err == nil {
+		t.Error("a number for a boolean passed")
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	type inner struct {
+		When time.Time `json:"when"`
+	}
+	type sample struct {
+		inner
+		Name    string         `json:"name"`
+		Count   int            `json:"count,omitempty"`
+		Ratio   *float64       `json:"ratio"`
+		Tags    []string       `json:"tags"`
+		Days    map[string]int `json:"days"`
+		Skipped string         `json:"-"`
+		hidden  bool
+	}
+	got, _ := json.Marshal(schemaOf(reflect.TypeOf(sample{})))
+	want := `{"properties":{"count":{"type":"integer"},"days":{"additionalProperties":{"type":"integer"},"type":"object"},` +
+		`"name":{"type":"string"},"ratio":{"type":"number"},"tags":{"items":{"type":"string"},"type":"array"},` +
+		`"when":{"format":"date-time","type":"string"}},"required":["days","name","tags","when"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("schemaOf =\n%s\nwant\n%s", got, want)
+	}
+}
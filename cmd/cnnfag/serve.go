@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+	"github.com/wildsurfer/cnn-fear-and-greed-parse/v2/render"
+)
+
+// runServe serves live images over HTTP, so READMEs and wikis can embed a
+// badge or a gauge that follows the index. Results are cached for -ttl and
+// the same time is sent as Cache-Control, which image proxies such as
+// GitHub's honor.
+func runServe(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8080", "listen on `address`")
+	ttl := fs.Duration("ttl", 5*time.Minute, "how long to reuse a fetched result")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newImageServer(newResultCache(cnnfag.Get, *ttl), timeout).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintln(stderr, "cnnfag serve: listening on", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, "cnnfag serve:", err)
+		return 1
+	}
+	return 0
+}
+
+// imageServer answers /badge.svg, /gauge.svg, /gauge.png, /chart.svg,
+// /chart.png and /indicators.png. Query parameters: label for the badge,
+// width for all but the badge, height and indicator for the charts.
+// Encoded images are kept for the cache's ttl too, so a popular embed is
+// drawn once per result rather than once per view.
+type imageServer struct {
+	cache   *resultCache
+	timeout time.Duration
+	images  *imageCache
+}
+
+func newImageServer(cache *resultCache, timeout time.Duration) imageServer {
+	return imageServer{cache: cache, timeout: timeout, images: &imageCache{entries: make(map[string]imageEntry)}}
+}
+
+// maxCachedImages bounds the image cache, since every query string is a
+// key: past it, expired entries are dropped and new images go uncached
+// until there is room.
+const maxCachedImages = 256
+
+// imageCache holds encoded images by path and query.
+type imageCache struct {
+	mu      sync.Mutex
+	entries map[string]imageEntry
+}
+
+type imageEntry struct {
+	body []byte
+	at   time.Time
+}
+
+// get returns the image stored under key if it is younger than ttl.
+func (c *imageCache) get(key string, ttl time.Duration) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || now().Sub(e.at) >= ttl {
+		return nil, false
+	}
+	return e.body, true
+}
+
+func (c *imageCache) put(key string, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := now()
+	if len(c.entries) >= maxCachedImages {
+		for k, e := range c.entries {
+			if t.Sub(e.at) >= ttl {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedImages {
+			return
+		}
+	}
+	c.entries[key] = imageEntry{body: body, at: t}
+}
+
+func (s imageServer) handler() http.Handler {
+	const svg, pngType = "image/svg+xml", "image/png"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/badge.svg", s.image(svg, func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		label := q.Get("label")
+		if label == "" {
+			label = "fear & greed"
+		}
+		return render.Badge(label, res.Score, res.Rating), nil
+	}))
+	mux.HandleFunc("/gauge.svg", s.image(svg, func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		width, err := sizeParam(q, "width", 600)
+		if err != nil {
+			return nil, err
+		}
+		return render.GaugeSVG(res, width), nil
+	}))
+	mux.HandleFunc("/gauge.png", s.image(pngType, func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		width, err := sizeParam(q, "width", 600)
+		if err != nil {
+			return nil, err
+		}
+		return encodePNG(render.Gauge(res, width))
+	}))
+	chart := func(res cnnfag.Result, q url.Values) (series []cnnfag.Value, id string, width, height int, err error) {
+		id = q.Get("indicator")
+		if id == "" {
+			id = "index"
+		}
+		if !validSeries(id) {
+			return nil, "", 0, 0, fmt.Errorf("unknown indicator %q", id)
+		}
+		if width, err = sizeParam(q, "width", 800); err != nil {
+			return nil, "", 0, 0, err
+		}
+		if height, err = sizeParam(q, "height", 400); err != nil {
+			return nil, "", 0, 0, err
+		}
+		series, _ = res.Series(id)
+		return series, id, width, height, nil
+	}
+	mux.HandleFunc("/chart.svg", s.image(svg, func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		series, id, width, height, err := chart(res, q)
+		if err != nil {
+			return nil, err
+		}
+		return render.ChartSVG(series, width, height, id == "index"), nil
+	}))
+	mux.HandleFunc("/chart.png", s.image(pngType, func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		series, id, width, height, err := chart(res, q)
+		if err != nil {
+			return nil, err
+		}
+		return encodePNG(render.Chart(series, width, height, id == "index"))
+	}))
+	mux.HandleFunc("/indicators.png", s.image(pngType, func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		width, err := sizeParam(q, "width", 1200)
+		if err != nil {
+			return nil, err
+		}
+		height, err := sizeParam(q, "height", 600)
+		if err != nil {
+			return nil, err
+		}
+		return encodePNG(render.Indicators(res, width, height))
+	}))
+	return mux
+}
+
+// image adapts draw to an http.HandlerFunc. A failed fetch is a 502, an
+// error from draw a bad request. Images are cached by path and query, with
+// the parameters sorted so their order does not matter.
+func (s imageServer) image(contentType string, draw func(cnnfag.Result, url.Values) ([]byte, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		key := r.URL.Path + "?" + q.Encode()
+		body, ok := s.images.get(key, s.cache.ttl)
+		if !ok {
+			ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+			defer cancel()
+
+			res, err := s.cache.get(ctx)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			if body, err = draw(res, q); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.images.put(key, body, s.cache.ttl)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.cache.ttl.Seconds())))
+		w.Write(body)
+	}
+}
+
+// maxImageSize caps width and height: a large PNG takes long enough to draw
+// and encode that a handful of requests could tie the server up.
+const maxImageSize = 1200
+
+// sizeParam reads a pixel size from the query, def if absent.
+func sizeParam(q url.Values, name string, def int) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxImageSize {
+		return 0, fmt.Errorf("%s must be a number of pixels from 1 to %d", name, maxImageSize)
+	}
+	return n, nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
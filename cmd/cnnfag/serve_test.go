@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestImageServer(t *testing.T) {
+	calls := 0
+	res := cnnfag.Result{Score: 64.37, Rating: "greed", History: []cnnfag.Point{
+		{Date: time.Date(2026, 8, 12, 0, 0, 0, 0, time.UTC), Score: 60, Rating: "greed"},
+		{Date: time.Date(2026, 8, 13, 0, 0, 0, 0, time.UTC), Score: 64.37, Rating: "greed"},
+	}}
+	cache := newResultCache(func(context.Context) (cnnfag.Result, error) {
+		calls++
+		return res, nil
+	}, time.Minute)
+	srv := httptest.NewServer(newImageServer(cache, time.Second).handler())
+	defer srv.Close()
+
+	get := func(path string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get("/badge.svg?label=mood")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("badge: %s %s", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(body, "mood: 64 greed") {
+		t.Errorf("badge body:\n%s", body)
+	}
+	if got := resp.Header.Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	for _, path := range []string{"/gauge.svg?width=300", "/chart.svg?indicator=index", "/gauge.png?width=200", "/chart.png?width=300&height=150", "/indicators.png?width=600&height=300"} {
+		resp, body := get(path)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: %s %s", path, resp.Status, body)
+			continue
+		}
+		if strings.Contains(path, ".png") {
+			if _, err := png.Decode(strings.NewReader(body)); err != nil {
+				t.Errorf("%s: %v", path, err)
+			}
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1 within the ttl", calls)
+	}
+
+	for _, path := range []string{"/chart.svg?indicator=gold", "/gauge.png?width=0", "/gauge.svg?width=big", "/indicators.png?height=1201"} {
+		if resp, _ := get(path); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: %s, want 400", path, resp.Status)
+		}
+	}
+	if resp, _ := get("/missing.svg"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("/missing.svg: %s, want 404", resp.Status)
+	}
+
+	down := newImageServer(newResultCache(func(context.Context) (cnnfag.Result, error) {
+		return cnnfag.Result{}, errors.New("down")
+	}, time.Minute), time.Second)
+	rec := httptest.NewRecorder()
+	down.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/badge.svg", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("badge with CNN down: %d, want 502", rec.Code)
+	}
+}
+
+func TestImageServerCachesImages(t *testing.T) {
+	clock := time.Date(2026, 8, 13, 20, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	cache := newResultCache(func(context.Context) (cnnfag.Result, error) {
+		return cnnfag.Result{Score: 64.37, Rating: "greed"}, nil
+	}, time.Minute)
+	draws := 0
+	h := newImageServer(cache, time.Second).image("image/svg+xml", func(res cnnfag.Result, q url.Values) ([]byte, error) {
+		draws++
+		if q.Get("width") == "bad" {
+			return nil, errors.New("bad width")
+		}
+		return []byte("<svg/>"), nil
+	})
+	get := func(target string) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec.Code
+	}
+
+	get("/gauge.svg?width=300&label=a")
+	get("/gauge.svg?label=a&width=300")
+	if draws != 1 {
+		t.Errorf("drew %d times, want 1 for the same query in another order", draws)
+	}
+	get("/gauge.svg?width=400")
+	if draws != 2 {
+		t.Errorf("drew %d times, want 2 after a new query", draws)
+	}
+
+	get("/gauge.svg?width=bad")
+	if code := get("/gauge.svg?width=bad"); code != http.StatusBadRequest || draws != 4 {
+		t.Errorf("bad request: %d after %d draws, want 400 and errors left uncached", code, draws)
+	}
+
+	clock = clock.Add(time.Minute)
+	get("/gauge.svg?width=300&label=a")
+	if draws != 5 {
+		t.Errorf("drew %d times, want the image redrawn after the ttl", draws)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// subscriptionPoll is how often a session with subscriptions polls CNN. The
+// index moves during US trading hours and CNN refreshes it every few
+// minutes, so polling faster only costs requests.
+const subscriptionPoll = 5 * time.Minute
+
+func subscriptionURI(params json.RawMessage) (string, *rpcError) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.URI == "" {
+		return "", &rpcError{-32602, "invalid params"}
+	}
+	if _, _, rerr := parseResourceURI(p.URI); rerr != nil {
+		return "", rerr
+	}
+	return p.URI, nil
+}
+
+// subscribe adds a resource to the ones the session watches, and starts the
+// poller if it is the first.
+func (s *mcpSession) subscribe(params json.RawMessage) (any, *rpcError) {
+	uri, rerr := subscriptionURI(params)
+	if rerr != nil {
+		return nil, rerr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		s.subs = map[string]bool{}
+	}
+	s.subs[uri] = true
+	if !s.polling {
+		s.polling = true
+		s.wg.Add(1)
+		go s.poll()
+	}
+	return struct{}{}, nil
+}
+
+// unsubscribe removes a resource; the poller stops at its next round when
+// none are left. Unsubscribing from a resource not subscribed is no error.
+func (s *mcpSession) unsubscribe(params json.RawMessage) (any, *rpcError) {
+	uri, rerr := subscriptionURI(params)
+	if rerr != nil {
+		return nil, rerr
+	}
+	s.mu.Lock()
+	delete(s.subs, uri)
+	s.mu.Unlock()
+	return struct{}{}, nil
+}
+
+// poll fetches the index every pollEvery until the session ends or has no
+// subscriptions, and notifies the subscribed resources when CNN publishes a
+// new reading, which Result.Timestamp marks. The first fetch only sets the
+// baseline; failed fetches are retried at the next round.
+func (s *mcpSession) poll() {
+	defer s.wg.Done()
+	var last time.Time
+	for {
+		ctx, cancel := context.WithTimeout(s.ctx, requestTimeout)
+		res, err := s.fetch(ctx)
+		cancel()
+		if err == nil {
+			if !last.IsZero() && !res.Timestamp.Equal(last) {
+				s.notify(res)
+			}
+			last = res.Timestamp
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.after(s.pollEvery):
+		}
+
+		s.mu.Lock()
+		if len(s.subs) == 0 {
+			s.polling = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+	}
+}
+
+// notify sends notifications/resources/updated for every subscribed
+// resource that res changes: all but the days of fng://history before the
+// new reading's.
+func (s *mcpSession) notify(res cnnfag.Result) {
+	day := res.Timestamp.UTC().Truncate(24 * time.Hour)
+	s.mu.Lock()
+	var uris []string
+	for uri := range s.subs {
+		if _, date, _ := parseResourceURI(uri); date.IsZero() || !date.Before(day) {
+			uris = append(uris, uri)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, uri := range uris {
+		// A write error ends the session through the request loop, which
+		// writes to the same stream.
+		s.out.send(rpcNotification{
+			JSONRPC: "2.0",
+			Method:  "notifications/resources/updated",
+			Params:  map[string]string{"uri": uri},
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestSubscriptions(t *testing.T) {
+	// The test hands the poller each fetch result and each tick, so every
+	// step happens in a known order.
+	results := make(chan cnnfag.Result)
+	ticks := make(chan time.Time)
+	fetch := func(ctx context.Context) (cnnfag.Result, error) {
+		select {
+		case res := <-results:
+			return res, nil
+		case <-ctx.Done():
+			return cnnfag.Result{}, ctx.Err()
+		}
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s := &mcpSession{
+		fetch:     fetch,
+		pollEvery: time.Minute,
+		after:     func(time.Duration) <-chan time.Time { return ticks },
+	}
+	done := make(chan error)
+	go func() {
+		err := s.serve(inR, &mcpWriter{enc: json.NewEncoder(outW)})
+		outW.Close()
+		done <- err
+	}()
+
+	out := bufio.NewScanner(outR)
+	id := 0
+	request := func(method, uri string) string {
+		t.Helper()
+		id++
+		fmt.Fprintf(inW, `{"jsonrpc":"2.0","id":%d,"method":%q,"params":{"uri":%q}}`+"\n", id, method, uri)
+		if !out.Scan() {
+			t.Fatalf("%s %s: no response", method, uri)
+		}
+		return out.Text()
+	}
+	reading := func(d int) cnnfag.Result {
+		return cnnfag.Result{Timestamp: time.Date(2026, 3, d, 20, 0, 0, 0, time.UTC)}
+	}
+
+	if got := request("resources/subscribe", "fng://current"); got != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Fatalf("subscribe response: %s", got)
+	}
+	results <- reading(9) // the baseline
+
+	// Subscriptions beyond the first share the poller. A past day's
+	// history does not change with a new reading.
+	request("resources/subscribe", "fng://indicators/vix")
+	request("resources/subscribe", "fng://history/2026-03-02")
+	if got := request("resources/subscribe", "fng://indicators/dow"); got != `{"jsonrpc":"2.0","id":4,"error":{"code":-32002,"message":"resource not found: fng://indicators/dow"}}` {
+		t.Errorf("subscribe to an unknown resource: %s", got)
+	}
+
+	// The same reading again notifies nothing; a new one notifies each
+	// affected subscription once.
+	ticks <- time.Time{}
+	results <- reading(9)
+	ticks <- time.Time{}
+	results <- reading(10)
+	var updated []string
+	for len(updated) < 2 && out.Scan() {
+		var n struct {
+			Method string `json:"method"`
+			Params struct {
+				URI string `json:"uri"`
+			} `json:"params"`
+		}
+		mustUnmarshal(t, out.Text(), &n)
+		if n.Method != "notifications/resources/updated" {
+			t.Fatalf("got %s, want an update notification", out.Text())
+		}
+		updated = append(updated, n.Params.URI)
+	}
+	sort.Strings(updated)
+	if fmt.Sprint(updated) != "[fng://current fng://indicators/vix]" {
+		t.Errorf("updated %v", updated)
+	}
+
+	// After unsubscribing from everything, the next round stops the poller.
+	request("resources/unsubscribe", "fng://current")
+	request("resources/unsubscribe", "fng://indicators/vix")
+	request("resources/unsubscribe", "fng://history/2026-03-02")
+	if got := request("resources/unsubscribe", "fng://current"); got != `{"jsonrpc":"2.0","id":8,"result":{}}` {
+		t.Errorf("repeated unsubscribe: %s", got)
+	}
+	ticks <- time.Time{}
+
+	// Closing stdin ends the session with no output after the responses.
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if out.Scan() {
+		t.Errorf("unexpected output after the session: %s", out.Text())
+	}
+	if s.polling {
+		t.Error("the poller did not stop")
+	}
+}
+
+func TestSubscriptionsEndWithSession(t *testing.T) {
+	// A session that ends with subscriptions stops its poller mid-wait.
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s := &mcpSession{
+		fetch:     func(context.Context) (cnnfag.Result, error) { return cnnfag.Result{}, nil },
+		pollEvery: time.Hour,
+		after:     time.After,
+	}
+	done := make(chan error)
+	go func() { done <- s.serve(inR, &mcpWriter{enc: json.NewEncoder(outW)}) }()
+
+	out := bufio.NewScanner(outR)
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"fng://current"}}`)
+	out.Scan()
+	inW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not return")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+	"github.com/wildsurfer/cnn-fear-and-greed-parse/v2/render"
+)
+
+// runSVG writes the gauge, the history chart or a status badge as SVG.
+func runSVG(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag svg", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("out", "-", "write the SVG to `path`, or - for stdout")
+	kind := fs.String("kind", "gauge", "image to draw: gauge, chart or badge")
+	indicator := fs.String("indicator", "index", "with -kind chart, the series: index or one of "+strings.Join(cnnfag.IndicatorIDs, ", "))
+	width := fs.Int("width", 0, "image width (default 600 for gauge, 800 for chart)")
+	height := fs.Int("height", 400, "with -kind chart, the image height")
+	label := fs.String("label", "fear & greed", "with -kind badge, the text on the left")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	widths := map[string]int{"gauge": 600, "chart": 800, "badge": 0}
+	def, ok := widths[*kind]
+	switch {
+	case !ok:
+		fmt.Fprintf(stderr, "cnnfag svg: unknown kind %q\n", *kind)
+		return 2
+	case !validSeries(*indicator):
+		fmt.Fprintf(stderr, "cnnfag svg: unknown indicator %q\n", *indicator)
+		return 2
+	case *width < 0 || *height < 1 || *width > 10000 || *height > 10000:
+		fmt.Fprintln(stderr, "cnnfag svg: -width and -height must be between 1 and 10000")
+		return 2
+	}
+	if *width == 0 {
+		*width = def
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := cnnfag.Get(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag svg:", err)
+		return 1
+	}
+
+	var svg []byte
+	switch *kind {
+	case "gauge":
+		svg = render.GaugeSVG(res, *width)
+	case "chart":
+		series, _ := res.Series(*indicator)
+		svg = render.ChartSVG(series, *width, *height, *indicator == "index")
+	case "badge":
+		svg = render.Badge(*label, res.Score, res.Rating)
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		_, err := w.Write(svg)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "cnnfag svg:", err)
+		return 1
+	}
+	return 0
+}
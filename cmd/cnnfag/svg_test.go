@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunSVG(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"svg"}, `width="600"`},
+		{[]string{"svg", "-kind", "chart", "-indicator", "vix"}, "14.5 (extreme fear)"},
+		{[]string{"svg", "-kind", "badge", "-label", "market mood"}, "market mood: 64 greed"},
+	} {
+		var stdout, stderr strings.Builder
+		if code := run(tc.args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("run(%v) = %d, stderr: %s", tc.args, code, stderr.String())
+		}
+		if !strings.HasPrefix(stdout.String(), "<svg ") || !strings.Contains(stdout.String(), tc.want) {
+			t.Errorf("run(%v) printed:\n%s", tc.args, stdout.String())
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "badge.svg")
+	if code := run([]string{"svg", "-kind", "badge", "-out", out}, strings.NewReader(""), new(strings.Builder), new(strings.Builder)); code != 0 {
+		t.Fatalf("run(svg -out) = %d", code)
+	}
+	if b, err := os.ReadFile(out); err != nil || !strings.Contains(string(b), "fear &amp; greed") {
+		t.Errorf("badge file: %v\n%s", err, b)
+	}
+
+	if code := run([]string{"svg", "-kind", "pie"}, strings.NewReader(""), new(strings.Builder), new(strings.Builder)); code != 2 {
+		t.Errorf("run(svg -kind pie) = %d, want 2", code)
+	}
+}
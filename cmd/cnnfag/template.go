@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// now is time.Now, swapped in tests so "ago" is stable.
+var now = time.Now
+
+// templateFuncs are the helpers -format templates get on top of text/template's
+// builtins. "indicator" is bound to the fetched result just before execution.
+var templateFuncs = template.FuncMap{
+	// round x rounds to an integer, round x n to n decimal places.
+	"round": func(x float64, places ...int) float64 {
+		p := math.Pow(10, float64(firstOr(places, 0)))
+		return math.Round(x*p) / p
+	},
+	// color rating text wraps text in the ANSI color of a rating, unless
+	// NO_COLOR is set.
+	"color": func(rating string, text any) string {
+		s := fmt.Sprint(text)
+		if os.Getenv("NO_COLOR") != "" {
+			return s
+		}
+		return colorize(rating, s)
+	},
+	// ago t describes t relative to now: "5m ago", "3h ago", "2d ago".
+	"ago": func(t time.Time) string {
+		return relativeTime(now().Sub(t))
+	},
+	"indicator": func(string) (cnnfag.Indicator, error) {
+		return cnnfag.Indicator{}, errors.New("indicator is only available for CNN's index")
+	},
+}
+
+func firstOr(xs []int, def int) int {
+	if len(xs) == 0 {
+		return def
+	}
+	return xs[0]
+}
+
+func relativeTime(d time.Duration) string {
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm %s", int(d.Minutes()), suffix)
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh %s", int(d.Hours()), suffix)
+	default:
+		return fmt.Sprintf("%dd %s", int(d.Hours()/24), suffix)
+	}
+}
+
+// parseTemplate returns the template given by -format or -template-file, or
+// nil if neither is set.
+func parseTemplate(format, file string) (*template.Template, error) {
+	switch {
+	case format != "" && file != "":
+		return nil, errors.New("-format and -template-file are mutually exclusive")
+	case file != "":
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		format = string(text)
+	case format == "":
+		return nil, nil
+	}
+	return template.New("format").Funcs(templateFuncs).Parse(format)
+}
+
+// executeTemplate runs tmpl against data, a cnnfag.Result or a
+// cnnfag.Snapshot, and ends the output with a newline if the template
+// does not.
+func executeTemplate(w io.Writer, tmpl *template.Template, data any) error {
+	if res, ok := data.(cnnfag.Result); ok {
+		tmpl = tmpl.Funcs(template.FuncMap{
+			"indicator": func(id string) (cnnfag.Indicator, error) {
+				ind, ok := res.Indicator(id)
+				if !ok {
+					return ind, fmt.Errorf("unknown indicator %q, the IDs are %s", id, strings.Join(cnnfag.IndicatorIDs, ", "))
+				}
+				return ind, nil
+			},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestRunTemplate(t *testing.T) {
+	fixture, err := os.ReadFile("../../testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := cnnfag.HTTPClient
+	cnnfag.HTTPClient = &http.Client{Transport: fixtureTransport{fixture}}
+	defer func() { cnnfag.HTTPClient = old }()
+
+	t.Setenv("NO_COLOR", "")
+	oldNow := now
+	now = func() time.Time { return time.Date(2026, 8, 11, 3, 30, 0, 0, time.UTC) }
+	defer func() { now = oldNow }()
+
+	file := filepath.Join(t.TempDir(), "motd.tmpl")
+	if err := os.WriteFile(file, []byte("F&G {{round .Score}}, VIX {{(indicator \"vix\").Rating}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-format", `{{.Score | printf "%.0f"}} {{.Rating}}`}, "64 greed\n"},
+		{[]string{"-format", `{{round .OneWeekAgo 1}} updated {{ago .Timestamp}}`}, "60 updated 3h ago\n"},
+		{[]string{"-format", `{{color .Rating .Rating}}`}, "\x1b[32mgreed\x1b[0m\n"},
+		{[]string{"-template-file", file}, "F&G 64, VIX neutral\n"},
+	} {
+		var stdout, stderr strings.Builder
+		if code := run(tc.args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+			t.Fatalf("run(%q) = %d, stderr: %s", tc.args, code, stderr.String())
+		}
+		if stdout.String() != tc.want {
+			t.Errorf("run(%q) = %q, want %q", tc.args, stdout.String(), tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"-format", "{{.Score"}, 2},
+		{[]string{"-format", "x", "-json"}, 2},
+		{[]string{"-format", "x", "-template-file", file}, 2},
+		{[]string{"-format", `{{(indicator "gold").Score}}`}, 1},
+	} {
+		var stdout, stderr strings.Builder
+		if code := run(tc.args, strings.NewReader(""), &stdout, &stderr); code != tc.code {
+			t.Errorf("run(%q) = %d, want %d", tc.args, code, tc.code)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		20 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		30 * time.Hour:   "30h ago",
+		72 * time.Hour:   "3d ago",
+		-2 * time.Hour:   "2h from now",
+	} {
+		if got := relativeTime(d); got != want {
+			t.Errorf("relativeTime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
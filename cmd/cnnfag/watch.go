@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // market hours need America/New_York, also in the scratch image
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// runWatch polls the index until interrupted, redrawing a full-screen
+// dashboard on a terminal or printing a line per update otherwise.
+func runWatch(args []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cnnfag watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	interval := fs.Duration("interval", 5*time.Minute, "time between refreshes")
+	marketHours := fs.Bool("market-hours", false, "refresh only while the NYSE is open, 9:30-16:00 New York time on weekdays")
+	plain := fs.Bool("plain", false, "print a line per update instead of a dashboard; the default when stdout is not a terminal")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *interval < time.Second {
+		fmt.Fprintln(stderr, "cnnfag watch: -interval must be at least 1s")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w := &watcher{
+		fetch:       cnnfag.Get,
+		timeout:     timeout,
+		interval:    *interval,
+		marketHours: *marketHours,
+		plain:       *plain || !isTerminal(stdout),
+		color:       useColor(stdout),
+		width:       terminalWidth(),
+		out:         stdout,
+		errOut:      stderr,
+		after:       time.After,
+	}
+	if !w.plain {
+		// The alternate screen keeps the shell's scrollback intact, and
+		// leaving it on Ctrl-C puts the terminal back as it was.
+		fmt.Fprint(stdout, "\x1b[?1049h\x1b[?25l")
+		defer fmt.Fprint(stdout, "\x1b[?25h\x1b[?1049l")
+	}
+	w.run(ctx)
+	return 0
+}
+
+// terminalWidth reads the width the shell exports in COLUMNS, 80 if unset.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// watcher is the refresh loop of cnnfag watch.
+type watcher struct {
+	fetch       func(context.Context) (cnnfag.Result, error)
+	timeout     time.Duration
+	interval    time.Duration
+	marketHours bool
+	plain       bool
+	color       bool
+	width       int
+	out, errOut io.Writer
+	after       func(time.Duration) <-chan time.Time // time.After, swapped in tests
+}
+
+// run fetches, draws and waits until ctx is done. Failed fetches are
+// reported and retried at the next refresh; the dashboard keeps showing the
+// last good result meanwhile.
+func (w *watcher) run(ctx context.Context) {
+	var prev, cur *cnnfag.Result
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, w.timeout)
+		res, err := w.fetch(fetchCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			prev, cur = cur, &res
+		}
+
+		t := now()
+		wait := w.interval
+		if w.marketHours && !marketOpen(t) {
+			wait = nextOpen(t).Sub(t)
+		}
+
+		switch {
+		case w.plain && err != nil:
+			fmt.Fprintf(w.errOut, "%s cnnfag watch: %v\n", t.Format(time.RFC3339), err)
+		case w.plain:
+			fmt.Fprintln(w.out, updateLine(t, prev, *cur))
+		default:
+			fmt.Fprint(w.out, "\x1b[H\x1b[2J"+w.dashboard(t, t.Add(wait), prev, cur, err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.after(wait):
+		}
+	}
+}
+
+// updateLine is one line of -plain output: refresh time, score, rating,
+// the change since the previous refresh and CNN's update time.
+func updateLine(t time.Time, prev *cnnfag.Result, cur cnnfag.Result) string {
+	line := fmt.Sprintf("%s %.1f %s", t.Format(time.RFC3339), cur.Score, cur.Rating)
+	if prev != nil {
+		line += " " + formatDelta(cur.Score-prev.Score)
+	}
+	return line + " updated " + cur.Timestamp.Format(time.RFC3339)
+}
+
+// formatDelta prints a score change to one decimal, "=" for none.
+func formatDelta(d float64) string {
+	s := strconv.FormatFloat(d, 'f', 1, 64)
+	switch {
+	case s == "0.0" || s == "-0.0":
+		return "="
+	case d > 0:
+		return "+" + s
+	default:
+		return s
+	}
+}
+
+// dashboard draws one screen: the headline with its change since the last
+// refresh, the gauge, the indicators with theirs, a sparkline of the last
+// months and a status line. Changes are shown in reverse video on a color
+// terminal and starred otherwise.
+func (w *watcher) dashboard(t, next time.Time, prev, cur *cnnfag.Result, err error) string {
+	var b strings.Builder
+	highlight := func(s string) string {
+		if w.color {
+			return "\x1b[7m" + s + colorReset
+		}
+		return "*" + s + "*"
+	}
+
+	fmt.Fprintln(&b, "CNN Fear & Greed Index")
+	fmt.Fprintln(&b)
+	if cur == nil {
+		fmt.Fprintf(&b, "waiting for data: %v\n", err)
+		return b.String()
+	}
+
+	headline := fmt.Sprintf("%.1f %s", cur.Score, cur.Rating)
+	if w.color {
+		headline = colorize(cur.Rating, headline)
+	}
+	if prev != nil {
+		if d := formatDelta(cur.Score - prev.Score); d != "=" {
+			headline += "  " + highlight(d+" since last refresh")
+		}
+	}
+	fmt.Fprintf(&b, "%s   updated %s\n\n", headline, cur.Timestamp.Local().Format("Mon 15:04"))
+	b.WriteString(gauge(*cur, min(max(w.width-2, 20), 60), w.color, false))
+	fmt.Fprintln(&b)
+
+	rows := make([][]string, 0, len(cnnfag.IndicatorIDs))
+	changed := make([]bool, 0, len(cnnfag.IndicatorIDs))
+	ratings := make([]string, 0, len(cnnfag.IndicatorIDs))
+	for _, id := range cnnfag.IndicatorIDs {
+		ind, _ := cur.Indicator(id)
+		change := ""
+		if prev != nil {
+			old, _ := prev.Indicator(id)
+			change = formatDelta(ind.Score - old.Score)
+		}
+		rows = append(rows, []string{indicatorInfo[id].name, strconv.FormatFloat(ind.Score, 'f', 0, 64), ind.Rating, change})
+		changed = append(changed, change != "" && change != "=")
+		ratings = append(ratings, ind.Rating)
+	}
+	writeAligned(&b, []string{"INDICATOR", "SCORE", "RATING", "CHANGE"}, rows, func(row, col int, cell string) string {
+		switch {
+		case col == 3 && changed[row]:
+			return highlight(cell)
+		case col == 2 && w.color:
+			return colorize(ratings[row], cell)
+		}
+		return cell
+	})
+
+	if series, _ := cur.Series("index"); len(series) > 0 {
+		fmt.Fprintf(&b, "\nhistory %s\n", sparkline(series, min(max(w.width-14, 10), 90), true, w.color))
+	}
+
+	status := fmt.Sprintf("refreshed %s, next %s", t.Format("15:04:05"), next.Format("15:04:05"))
+	if w.marketHours && !marketOpen(t) {
+		status = fmt.Sprintf("refreshed %s, market closed until %s", t.Format("15:04:05"), next.In(newYork).Format("Mon 15:04 MST"))
+	}
+	if err != nil {
+		status += fmt.Sprintf(" (last refresh failed: %v)", err)
+	}
+	fmt.Fprintf(&b, "\n%s · Ctrl-C to quit\n", status)
+	return b.String()
+}
+
+var newYork = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}()
+
+// Regular NYSE session, New York time.
+const (
+	marketOpens  = 9*time.Hour + 30*time.Minute
+	marketCloses = 16 * time.Hour
+)
+
+// marketOpen reports whether t falls in the NYSE's regular session.
+// Exchange holidays are not known, so on those days watch refreshes an
+// index that does not move.
+func marketOpen(t time.Time) bool {
+	t = t.In(newYork)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, newYork)
+	return !t.Before(day.Add(marketOpens)) && t.Before(day.Add(marketCloses))
+}
+
+// nextOpen returns the next session open after t.
+func nextOpen(t time.Time) time.Time {
+	t = t.In(newYork)
+	for day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, newYork); ; day = day.AddDate(0, 0, 1) {
+		open := day.Add(marketOpens)
+		if open.After(t) && day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			return open
+		}
+	}
+}
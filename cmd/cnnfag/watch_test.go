@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestMarketHours(t *testing.T) {
+	ny := func(s string) time.Time {
+		t.Helper()
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, newYork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	for _, tc := range []struct {
+		at   string
+		open bool
+		next string
+	}{
+		{"2026-08-13 09:29", false, "2026-08-13 09:30"},
+		{"2026-08-13 09:30", true, "2026-08-14 09:30"},
+		{"2026-08-13 15:59", true, "2026-08-14 09:30"},
+		{"2026-08-13 16:00", false, "2026-08-14 09:30"},
+		{"2026-08-14 17:00", false, "2026-08-17 09:30"}, // Friday evening
+		{"2026-08-16 12:00", false, "2026-08-17 09:30"}, // Sunday
+		{"2026-11-01 12:00", false, "2026-11-02 09:30"}, // the Sunday clocks go back
+	} {
+		at := ny(tc.at)
+		if got := marketOpen(at.UTC()); got != tc.open {
+			t.Errorf("marketOpen(%s) = %v, want %v", tc.at, got, tc.open)
+		}
+		if got := nextOpen(at.UTC()); !got.Equal(ny(tc.next)) {
+			t.Errorf("nextOpen(%s) = %s, want %s", tc.at, got.In(newYork), tc.next)
+		}
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	for d, want := range map[float64]string{1.26: "+1.3", -0.5: "-0.5", 0.01: "=", -0.04: "="} {
+		if got := formatDelta(d); got != want {
+			t.Errorf("formatDelta(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+// testWatcher runs a watcher over scores, one per refresh, with an error
+// for NaN, and returns stdout and stderr. It stops after the last score.
+func testWatcher(t *testing.T, plain bool, scores ...float64) (string, string) {
+	t.Helper()
+	clock := time.Date(2026, 8, 13, 14, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout, stderr strings.Builder
+	calls := 0
+	w := &watcher{
+		fetch: func(context.Context) (cnnfag.Result, error) {
+			s := scores[calls]
+			calls++
+			if calls == len(scores) {
+				defer cancel()
+			}
+			if math.IsNaN(s) {
+				return cnnfag.Result{}, errors.New("down")
+			}
+			res := cnnfag.Result{Score: s, Rating: cnnfag.RatingOf(s), Timestamp: time.Date(2026, 8, 13, 13, 0, 0, 0, time.UTC)}
+			res.History = []cnnfag.Point{{Date: res.Timestamp, Score: s, Rating: res.Rating}}
+			res.MarketVolatility = cnnfag.Indicator{Score: s / 2, Rating: cnnfag.RatingOf(s / 2)}
+			return res, nil
+		},
+		timeout:  time.Second,
+		interval: time.Minute,
+		plain:    plain,
+		width:    80,
+		out:      &stdout,
+		errOut:   &stderr,
+		after: func(d time.Duration) <-chan time.Time {
+			clock = clock.Add(d)
+			c := make(chan time.Time, 1)
+			c <- clock
+			return c
+		},
+	}
+	w.run(ctx)
+	// The last refresh is cut short by the cancel, like a Ctrl-C.
+	return stdout.String(), stderr.String()
+}
+
+func TestWatchPlain(t *testing.T) {
+	stdout, stderr := testWatcher(t, true, 60, 60, 62.5, 61, 0)
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	want := []string{
+		"2026-08-13T14:00:00Z 60.0 greed updated 2026-08-13T13:00:00Z",
+		"2026-08-13T14:01:00Z 60.0 greed = updated 2026-08-13T13:00:00Z",
+		"2026-08-13T14:02:00Z 62.5 greed +2.5 updated 2026-08-13T13:00:00Z",
+		"2026-08-13T14:03:00Z 61.0 greed -1.5 updated 2026-08-13T13:00:00Z",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("plain output:\n%s\nwant:\n%s", stdout, strings.Join(want, "\n"))
+	}
+	if stderr != "" {
+		t.Errorf("stderr: %s", stderr)
+	}
+
+	stdout, stderr = testWatcher(t, true, 60, math.NaN(), 64, 0)
+	if !strings.Contains(stderr, "2026-08-13T14:01:00Z cnnfag watch: down") {
+		t.Errorf("stderr = %q", stderr)
+	}
+	if !strings.Contains(stdout, "64.0 greed +4.0") {
+		t.Errorf("the refresh after a failure does not compare with the last good one:\n%s", stdout)
+	}
+}
+
+func TestWatchDashboard(t *testing.T) {
+	stdout, _ := testWatcher(t, false, 60, 64, 0)
+	screens := strings.Split(stdout, "\x1b[H\x1b[2J")[1:]
+	if len(screens) != 2 {
+		t.Fatalf("drew %d screens, want 2:\n%s", len(screens), stdout)
+	}
+	first, second := screens[0], screens[1]
+
+	for _, want := range []string{"60.0 greed", "▼ 60 greed", "Market volatility", "history ", "refreshed 14:00:00, next 14:01:00"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("first screen lacks %q:\n%s", want, first)
+		}
+	}
+	if strings.Contains(first, "*") {
+		t.Errorf("first screen highlights a change:\n%s", first)
+	}
+	for _, want := range []string{"*+4.0 since last refresh*", "*+2.0*"} {
+		if !strings.Contains(second, want) {
+			t.Errorf("second screen lacks %q:\n%s", want, second)
+		}
+	}
+	if strings.Contains(stdout, "\x1b[7m") {
+		t.Error("uncolored dashboard uses reverse video")
+	}
+}
@@ -1,7 +1,9 @@
 // Package cnnfag fetches CNN's Fear & Greed index.
 //
 // CNN has no documented public API; this package uses the JSON endpoint that
-// the https://www.cnn.com/markets/fear-and-greed page itself requests.
+// the https://www.cnn.com/markets/fear-and-greed page itself requests, and
+// falls back to the numbers rendered into that page when the endpoint
+// refuses to answer.
 package cnnfag
 
 import (
@@ -16,14 +18,27 @@ import (
 // var, not const: tests point it at an httptest server.
 var endpoint = "https://production.dataviz.cnn.io/index/fearandgreed/graphdata"
 
-// HTTPClient is the client Get uses. Replace it to set a timeout, a proxy or
-// a custom transport.
+// HTTPClient is the client Get and the built-in Sources use. Replace it to
+// set a timeout, a proxy or a custom transport.
 var HTTPClient = http.DefaultClient
 
 // ErrUnexpectedStatus is returned when CNN responds with a non-200 status.
 // CNN answers 418 when a request is missing browser-like headers.
 var ErrUnexpectedStatus = errors.New("unexpected http status")
 
+// statusError is ErrUnexpectedStatus with the status CNN answered.
+type statusError int
+
+func (e statusError) Error() string { return fmt.Sprintf("%v: %d", ErrUnexpectedStatus, int(e)) }
+func (e statusError) Unwrap() error { return ErrUnexpectedStatus }
+
+// refused reports whether err is CNN turning the request away, 403 or 418,
+// which another browser profile may get past.
+func refused(err error) bool {
+	var status statusError
+	return errors.As(err, &status) && (status == http.StatusForbidden || status == http.StatusTeapot)
+}
+
 // ErrEmptyResult is returned when CNN answers 200 but the payload carries no
 // index data, which most likely means the API schema changed.
 var ErrEmptyResult = errors.New("empty result, CNN may have changed the API schema")
@@ -62,6 +77,8 @@ type Indicator struct {
 // greed. Ratings are CNN's text labels for score bands: "extreme fear",
 // "fear", "neutral", "greed", "extreme greed".
 type Result struct {
+	// Source is the Name of the Source that produced the result.
+	Source string  `json:"source"`
 	Score  float64 `json:"score"`
 	Rating string  `json:"rating"`
 	// Timestamp is when CNN last updated the score.
@@ -87,14 +104,16 @@ type Result struct {
 
 type apiSeries struct {
 	// Timestamp is epoch milliseconds here; only fear_and_greed uses RFC 3339.
-	Timestamp float64 `json:"timestamp"`
-	Score     float64 `json:"score"`
-	Rating    string  `json:"rating"`
-	Data      []struct {
-		X      float64 `json:"x"`
-		Y      float64 `json:"y"`
-		Rating string  `json:"rating"`
-	} `json:"data"`
+	Timestamp float64    `json:"timestamp"`
+	Score     float64    `json:"score"`
+	Rating    string     `json:"rating"`
+	Data      []apiPoint `json:"data"`
+}
+
+type apiPoint struct {
+	X      float64 `json:"x"` // epoch milliseconds
+	Y      float64 `json:"y"`
+	Rating string  `json:"rating"`
 }
 
 type apiResponse struct {
@@ -134,16 +153,50 @@ func toIndicator(s apiSeries) Indicator {
 	return ind
 }
 
-// Get fetches the current Fear & Greed index from CNN.
+// Get fetches the current Fear & Greed index from CNN. It tries Sources in
+// turn, moving on only while CNN refuses them with 403 or 418, and returns
+// the first result. Any other failure ends the chain: an empty or
+// undecodable answer means the API changed, which the next source would hide.
+// The error joins those of every source tried, so errors.Is still finds
+// ErrUnexpectedStatus or ErrEmptyResult. A Trace attached with WithTrace sees
+// each attempt.
 func Get(ctx context.Context) (Result, error) {
+	if len(Sources) == 0 {
+		return Result{}, errors.New("no sources configured")
+	}
+
+	trace := contextTrace(ctx)
+	var errs []error
+	for _, src := range Sources {
+		if trace.SourceStart != nil {
+			trace.SourceStart(src.Name())
+		}
+		res, err := src.Fetch(ctx)
+		if trace.SourceDone != nil {
+			trace.SourceDone(src.Name(), err)
+		}
+		if err == nil {
+			res.Source = src.Name()
+			return res, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+		if !refused(err) {
+			break
+		}
+	}
+	return Result{}, errors.Join(errs...)
+}
+
+// fetchEndpoint requests the JSON endpoint with the given headers and decodes
+// the response.
+func fetchEndpoint(ctx context.Context, header http.Header) (Result, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return Result{}, fmt.Errorf("building request: %w", err)
 	}
-
-	// The endpoint returns 418 unless both headers are present.
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36")
-	req.Header.Set("Referer", "https://www.cnn.com/markets/fear-and-greed")
+	for k, v := range header {
+		req.Header[k] = v
+	}
 
 	res, err := HTTPClient.Do(req)
 	if err != nil {
@@ -152,7 +205,7 @@ func Get(ctx context.Context) (Result, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return Result{}, fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)
+		return Result{}, statusError(res.StatusCode)
 	}
 
 	var raw apiResponse
@@ -11,7 +11,16 @@ import (
 )
 
 // Tests live in package cnnfag so they can swap the unexported endpoint
-// variable; for the same reason they must not run in parallel.
+// variables; for the same reason they must not run in parallel.
+
+// useServer points the JSON endpoint and the page at url for the rest of the
+// test, so no fallback can reach CNN.
+func useServer(t *testing.T, url string) {
+	t.Helper()
+	oldEndpoint, oldPage := endpoint, pageURL
+	endpoint, pageURL = url, url
+	t.Cleanup(func() { endpoint, pageURL = oldEndpoint, oldPage })
+}
 
 func TestGet(t *testing.T) {
 	fixture, err := os.ReadFile("testdata/graphdata.json")
@@ -28,15 +37,16 @@ func TestGet(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	old := endpoint
-	endpoint = srv.URL
-	defer func() { endpoint = old }()
+	useServer(t, srv.URL)
 
 	result, err := Get(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
+	if result.Source != "graphdata" {
+		t.Errorf("Source = %q, want %q", result.Source, "graphdata")
+	}
 	if result.Score != 64.3714285714286 {
 		t.Errorf("Score = %v, want 64.3714285714286", result.Score)
 	}
@@ -107,9 +117,7 @@ func TestGetUnexpectedStatus(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	old := endpoint
-	endpoint = srv.URL
-	defer func() { endpoint = old }()
+	useServer(t, srv.URL)
 
 	_, err := Get(context.Background())
 	if !errors.Is(err, ErrUnexpectedStatus) {
@@ -123,9 +131,7 @@ func TestGetEmptyResult(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	old := endpoint
-	endpoint = srv.URL
-	defer func() { endpoint = old }()
+	useServer(t, srv.URL)
 
 	_, err := Get(context.Background())
 	if !errors.Is(err, ErrEmptyResult) {
@@ -142,6 +148,10 @@ func TestGetLive(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	// A fallback answering means CNN changed something the primary relies on.
+	if result.Source != "graphdata" {
+		t.Errorf("Source = %q, want the primary %q", result.Source, "graphdata")
+	}
 	if result.Score <= 0 || result.Score > 100 {
 		t.Errorf("Score = %v, want in (0, 100]", result.Score)
 	}
@@ -0,0 +1,170 @@
+package cnnfag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// var, not const: tests point it at an httptest server.
+var cryptoEndpoint = "https://api.alternative.me/fng/?limit=365"
+
+// Snapshot is what every sentiment index has in common, whoever publishes
+// it: a 0-100 score, a rating and a daily history.
+type Snapshot struct {
+	// Provider identifies the index, such as "cnn" or "crypto".
+	Provider  string    `json:"provider"`
+	Score     float64   `json:"score"`
+	Rating    string    `json:"rating"`
+	Timestamp time.Time `json:"timestamp"`
+	// History holds daily points, oldest first.
+	History []Point `json:"history,omitempty"`
+}
+
+// Provider fetches one fear/greed style index. Two are built in: "cnn", which
+// wraps Get, and "crypto", alternative.me's Crypto Fear & Greed Index.
+type Provider interface {
+	Snapshot(ctx context.Context) (Snapshot, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{
+		"cnn":    cnnProvider{},
+		"crypto": cryptoProvider{},
+	}
+)
+
+// RegisterProvider makes p available under name to LookupProvider, and so to
+// the CLI and the MCP server. Registering a name again replaces the earlier
+// provider.
+func RegisterProvider(name string, p Provider) {
+	if p == nil {
+		panic("cnnfag: RegisterProvider with a nil provider")
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// UnregisterProvider removes the provider registered under name, if any.
+func UnregisterProvider(name string) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	delete(providers, name)
+}
+
+// LookupProvider returns the provider registered under name.
+func LookupProvider(name string) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[name]
+	return p, ok
+}
+
+// ProviderNames returns the registered provider names, sorted.
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type cnnProvider struct{}
+
+func (cnnProvider) Snapshot(ctx context.Context) (Snapshot, error) {
+	res, err := Get(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{
+		Provider:  "cnn",
+		Score:     res.Score,
+		Rating:    res.Rating,
+		Timestamp: res.Timestamp,
+		History:   res.History,
+	}, nil
+}
+
+// cryptoProvider reads alternative.me's index, which rates the crypto market
+// on the same 0-100 scale and with the same five labels as CNN.
+type cryptoProvider struct{}
+
+type cryptoResponse struct {
+	Data []struct {
+		// alternative.me sends numbers as strings.
+		Value          string `json:"value"`
+		Classification string `json:"value_classification"`
+		Timestamp      string `json:"timestamp"` // epoch seconds
+	} `json:"data"`
+	Metadata struct {
+		Error *string `json:"error"`
+	} `json:"metadata"`
+}
+
+func (cryptoProvider) Snapshot(ctx context.Context) (Snapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cryptoEndpoint, nil)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("building request: %w", err)
+	}
+
+	res, err := HTTPClient.Do(req)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("fetching crypto fear and greed data: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Snapshot{}, statusError(res.StatusCode)
+	}
+
+	var raw cryptoResponse
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return Snapshot{}, fmt.Errorf("decoding response: %w", err)
+	}
+	if raw.Metadata.Error != nil {
+		return Snapshot{}, errors.New("crypto fear and greed api: " + *raw.Metadata.Error)
+	}
+	if len(raw.Data) == 0 {
+		return Snapshot{}, ErrEmptyResult
+	}
+
+	// The API lists the newest day first.
+	history := make([]Point, 0, len(raw.Data))
+	for i := len(raw.Data) - 1; i >= 0; i-- {
+		d := raw.Data[i]
+		score, err := strconv.ParseFloat(d.Value, 64)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("decoding value %q: %w", d.Value, err)
+		}
+		sec, err := strconv.ParseInt(d.Timestamp, 10, 64)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("decoding timestamp %q: %w", d.Timestamp, err)
+		}
+		history = append(history, Point{
+			Date:   time.Unix(sec, 0).UTC(),
+			Score:  score,
+			Rating: strings.ToLower(d.Classification),
+		})
+	}
+
+	latest := history[len(history)-1]
+	return Snapshot{
+		Provider:  "crypto",
+		Score:     latest.Score,
+		Rating:    latest.Rating,
+		Timestamp: latest.Date,
+		History:   history,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package cnnfag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCryptoProvider(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/crypto-fng.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	old := cryptoEndpoint
+	cryptoEndpoint = srv.URL
+	defer func() { cryptoEndpoint = old }()
+
+	p, ok := LookupProvider("crypto")
+	if !ok {
+		t.Fatal(`no "crypto" provider`)
+	}
+	snap, err := p.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if snap.Provider != "crypto" || snap.Score != 71 || snap.Rating != "greed" {
+		t.Errorf("snapshot = %s %v (%s), want crypto 71 (greed)", snap.Provider, snap.Score, snap.Rating)
+	}
+	if want := time.Date(2026, 8, 11, 0, 0, 0, 0, time.UTC); !snap.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", snap.Timestamp, want)
+	}
+	if len(snap.History) != 3 {
+		t.Fatalf("len(History) = %d, want 3", len(snap.History))
+	}
+	if first := snap.History[0]; first.Score != 22 || first.Rating != "extreme fear" {
+		t.Errorf("History[0] = %+v, want the oldest day, 22 (extreme fear)", first)
+	}
+}
+
+func TestCNNProvider(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	p, _ := LookupProvider("cnn")
+	snap, err := p.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snap.Provider != "cnn" || snap.Score != 64.3714285714286 || snap.Rating != "greed" || len(snap.History) != 3 {
+		t.Errorf("snapshot = %+v", snap)
+	}
+}
+
+type staticProvider Snapshot
+
+func (p staticProvider) Snapshot(context.Context) (Snapshot, error) { return Snapshot(p), nil }
+
+func TestRegisterProvider(t *testing.T) {
+	if got := ProviderNames(); !reflect.DeepEqual(got, []string{"cnn", "crypto"}) {
+		t.Errorf("ProviderNames() = %v, want the built-ins", got)
+	}
+
+	RegisterProvider("static", staticProvider{Provider: "static", Score: 10})
+	defer UnregisterProvider("static")
+
+	p, ok := LookupProvider("static")
+	if !ok {
+		t.Fatal("registered provider not found")
+	}
+	if snap, _ := p.Snapshot(context.Background()); snap.Score != 10 {
+		t.Errorf("Score = %v, want 10", snap.Score)
+	}
+	if got := ProviderNames(); !reflect.DeepEqual(got, []string{"cnn", "crypto", "static"}) {
+		t.Errorf("ProviderNames() = %v", got)
+	}
+	if _, ok := LookupProvider("nope"); ok {
+		t.Error("LookupProvider found an unregistered name")
+	}
+
+	UnregisterProvider("static")
+	if _, ok := LookupProvider("static"); ok {
+		t.Error("LookupProvider found an unregistered provider")
+	}
+}
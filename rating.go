@@ -0,0 +1,34 @@
+package cnnfag
+
+// Band is one of CNN's five rating bands on the 0-100 scale.
+type Band struct {
+	Rating   string
+	Min, Max float64
+}
+
+// Bands are CNN's rating bands, from extreme fear to extreme greed. Use
+// RatingOf rather than the bounds to label a score: CNN puts 25 and 45 in the
+// band above, 55 and 75 in the band below.
+var Bands = []Band{
+	{"extreme fear", 0, 25},
+	{"fear", 25, 45},
+	{"neutral", 45, 55},
+	{"greed", 55, 75},
+	{"extreme greed", 75, 100},
+}
+
+// RatingOf returns the label CNN gives a 0-100 score.
+func RatingOf(score float64) string {
+	switch {
+	case score < 25:
+		return "extreme fear"
+	case score < 45:
+		return "fear"
+	case score <= 55:
+		return "neutral"
+	case score <= 75:
+		return "greed"
+	default:
+		return "extreme greed"
+	}
+}
@@ -0,0 +1,23 @@
+package cnnfag
+
+import "testing"
+
+func TestRatingOf(t *testing.T) {
+	// The boundaries as CNN's own labels in testdata/graphdata.json place
+	// them: 44.2 is fear, 71.8 greed, 76.8 extreme greed.
+	for score, want := range map[float64]string{
+		0: "extreme fear", 24.9: "extreme fear", 25: "fear", 44.2: "fear",
+		45: "neutral", 55: "neutral", 55.1: "greed", 71.8: "greed", 75: "greed",
+		76.8: "extreme greed", 100: "extreme greed",
+	} {
+		if got := RatingOf(score); got != want {
+			t.Errorf("RatingOf(%v) = %q, want %q", score, got, want)
+		}
+	}
+
+	for _, b := range Bands {
+		if got := RatingOf((b.Min + b.Max) / 2); got != b.Rating {
+			t.Errorf("the middle of band %q rates %q", b.Rating, got)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package cnnfag
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrReplayDone is returned by a Replayer that has served every recording.
+var ErrReplayDone = errors.New("replay: no recordings left")
+
+// Recordings are raw HTTP responses, status line and headers included, one
+// per file. The file name is the UTC time of the recording, so names sort in
+// recording order.
+const (
+	recordingLayout = "20060102T150405.000000000Z"
+	recordingExt    = ".http"
+)
+
+// Recorder is an http.RoundTripper that saves every response it passes on,
+// successful or not, as a file in Dir. Install it in HTTPClient to collect
+// real payloads as test fixtures, and play them back with a Replayer.
+type Recorder struct {
+	// Dir receives the recordings. It is created if missing.
+	Dir string
+	// Transport makes the actual requests. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	t := r.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	res, err := t.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// DumpResponse reads the body and puts an equivalent one back.
+	dump, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("recording response: %w", err)
+	}
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("recording response: %w", err)
+	}
+	name := time.Now().UTC().Format(recordingLayout) + recordingExt
+	f, err := os.OpenFile(filepath.Join(r.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err == nil {
+		_, err = f.Write(dump)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("recording response: %w", err)
+	}
+	return res, nil
+}
+
+// Replayer is an http.RoundTripper that answers each request with the next
+// response saved by a Recorder, oldest first, whatever the request asks for.
+// Once all are served it returns ErrReplayDone. It is safe for concurrent
+// use.
+type Replayer struct {
+	mu    sync.Mutex
+	files []string
+	next  int
+	// repeat serves files[0] forever, see NewReplayerAt.
+	repeat bool
+}
+
+// NewReplayer returns a Replayer for the recordings in dir. It fails if dir
+// holds none.
+func NewReplayer(dir string) (*Replayer, error) {
+	recs, err := listRecordings(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, len(recs))
+	for i, rec := range recs {
+		files[i] = rec.path
+	}
+	return &Replayer{files: files}, nil
+}
+
+// NewReplayerAt returns a Replayer that answers every request with the
+// latest recording in dir made at or before asOf, never running out. With it
+// installed in HTTPClient, Get returns the index as it was known then, which
+// makes research runs reproducible; a later recording would leak the future
+// into a backtest, so NewReplayerAt fails if every recording is after asOf.
+func NewReplayerAt(dir string, asOf time.Time) (*Replayer, error) {
+	recs, err := listRecordings(dir)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(recs), func(i int) bool { return recs[i].at.After(asOf) })
+	if i == 0 {
+		return nil, fmt.Errorf("no recording in %s made at or before %s", dir, asOf.Format(time.RFC3339))
+	}
+	return &Replayer{files: []string{recs[i-1].path}, repeat: true}, nil
+}
+
+// Len reports how many recordings the Replayer holds in total.
+func (r *Replayer) Len() int {
+	return len(r.files)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.repeat {
+		return readRecording(r.files[0], req)
+	}
+
+	r.mu.Lock()
+	if r.next == len(r.files) {
+		r.mu.Unlock()
+		return nil, ErrReplayDone
+	}
+	path := r.files[r.next]
+	r.next++
+	r.mu.Unlock()
+
+	return readRecording(path, req)
+}
+
+type recording struct {
+	path string
+	at   time.Time
+}
+
+// listRecordings returns the recordings in dir, oldest first. Files whose
+// names are not recording times are ignored.
+func listRecordings(dir string) ([]recording, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading recordings: %w", err)
+	}
+	var recs []recording
+	for _, e := range entries {
+		stem, ok := strings.CutSuffix(e.Name(), recordingExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		at, err := time.Parse(recordingLayout, stem)
+		if err != nil {
+			continue
+		}
+		recs = append(recs, recording{path: filepath.Join(dir, e.Name()), at: at})
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("no recordings in %s", dir)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].at.Before(recs[j].at) })
+	return recs, nil
+}
+
+func readRecording(path string, req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("replaying recording: %w", err)
+	}
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return nil, fmt.Errorf("replaying %s: %w", filepath.Base(path), err)
+	}
+	return res, nil
+}
@@ -0,0 +1,181 @@
+package cnnfag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	synthetic := Synthetic(SyntheticOptions{Seed: 1, Days: 5, End: time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC)})
+
+	// The live server changes its answer between calls, as CNN does.
+	payloads := [][]byte{fixture, synthetic}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(payloads[0])
+		payloads = payloads[1:]
+	}))
+	defer srv.Close()
+
+	useServer(t, srv.URL)
+	old := HTTPClient
+	defer func() { HTTPClient = old }()
+
+	dir := t.TempDir() + "/recordings"
+	HTTPClient = &http.Client{Transport: &Recorder{Dir: dir}}
+	var live []Result
+	for i := 0; i < 2; i++ {
+		res, err := Get(context.Background())
+		if err != nil {
+			t.Fatalf("recording Get %d: %v", i, err)
+		}
+		live = append(live, res)
+	}
+
+	// Files that are not recordings are left alone.
+	if err := os.WriteFile(dir+"/README", []byte("fixtures"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayer.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", replayer.Len())
+	}
+	srv.Close() // replay must not need the network
+
+	HTTPClient = &http.Client{Transport: replayer}
+	for i, want := range live {
+		res, err := Get(context.Background())
+		if err != nil {
+			t.Fatalf("replayed Get %d: %v", i, err)
+		}
+		if res.Score != want.Score || !res.Timestamp.Equal(want.Timestamp) || len(res.History) != len(want.History) {
+			t.Errorf("replay %d = %v at %v, want %v at %v", i, res.Score, res.Timestamp, want.Score, want.Timestamp)
+		}
+	}
+	if _, err := Get(context.Background()); !errors.Is(err, ErrReplayDone) {
+		t.Errorf("err after the last recording = %v, want ErrReplayDone", err)
+	}
+
+	if _, err := NewReplayer(t.TempDir()); err == nil {
+		t.Error("NewReplayer on an empty directory succeeded")
+	}
+}
+
+func TestRecordAndReplaySourceChain(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// CNN refuses Chrome on the first Get, fails outright on the second and
+	// answers everyone on the third.
+	get := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case get == 0 && !strings.Contains(r.Header.Get("User-Agent"), "Firefox"):
+			w.WriteHeader(http.StatusTeapot)
+		case get == 1:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write(fixture)
+		}
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+	old := HTTPClient
+	defer func() { HTTPClient = old }()
+
+	dir := t.TempDir()
+	HTTPClient = &http.Client{Transport: &Recorder{Dir: dir}}
+	var live []string
+	for ; get < 3; get++ {
+		res, err := Get(context.Background())
+		live = append(live, fmt.Sprintf("%s %v", res.Source, errors.Is(err, ErrUnexpectedStatus)))
+	}
+	if want := []string{"graphdata-firefox false", " true", "graphdata false"}; !reflect.DeepEqual(live, want) {
+		t.Fatalf("recorded Gets = %q, want %q", live, want)
+	}
+
+	// One recording per request made: the refusal and the answer after it,
+	// the failure alone, the answer alone.
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayer.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", replayer.Len())
+	}
+	HTTPClient = &http.Client{Transport: replayer}
+	for i, want := range live {
+		res, err := Get(context.Background())
+		if got := fmt.Sprintf("%s %v", res.Source, errors.Is(err, ErrUnexpectedStatus)); got != want {
+			t.Errorf("replayed Get %d = %q, want %q", i, got, want)
+		}
+	}
+	if _, err := Get(context.Background()); !errors.Is(err, ErrReplayDone) {
+		t.Errorf("err after the last recording = %v, want ErrReplayDone", err)
+	}
+}
+
+func TestReplayerAt(t *testing.T) {
+	dir := t.TempDir()
+	for _, day := range []int{3, 10, 20} {
+		end := time.Date(2026, 4, day, 21, 0, 0, 0, time.UTC)
+		body := Synthetic(SyntheticOptions{Seed: int64(day), Days: 5, End: end})
+		name := end.Format(recordingLayout) + recordingExt
+		raw := "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n" + string(body)
+		if err := os.WriteFile(dir+"/"+name, []byte(raw), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := HTTPClient
+	defer func() { HTTPClient = old }()
+
+	// Never a recording made after asOf, however much nearer it is.
+	if _, err := NewReplayerAt(dir, time.Date(2026, 4, 3, 20, 59, 0, 0, time.UTC)); err == nil {
+		t.Error("NewReplayerAt before every recording: no error")
+	}
+	for _, tc := range []struct {
+		asOf time.Time
+		want int
+	}{
+		{time.Date(2026, 4, 3, 21, 0, 0, 0, time.UTC), 3},
+		{time.Date(2026, 4, 10, 20, 59, 0, 0, time.UTC), 3},
+		{time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(2026, 4, 19, 23, 0, 0, 0, time.UTC), 10},
+		{time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), 20},
+	} {
+		replayer, err := NewReplayerAt(dir, tc.asOf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		HTTPClient = &http.Client{Transport: replayer}
+		// The snapshot never runs out.
+		for i := 0; i < 3; i++ {
+			res, err := Get(context.Background())
+			if err != nil {
+				t.Fatalf("as of %v: %v", tc.asOf, err)
+			}
+			if res.Timestamp.Day() != tc.want {
+				t.Errorf("as of %v: got the snapshot of %v, want April %d", tc.asOf, res.Timestamp, tc.want)
+			}
+		}
+	}
+}
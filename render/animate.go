@@ -0,0 +1,82 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Animation animates the gauge through points, one frame each, with the
+// date under the rating: the needle walks through a stretch of
+// Result.History for a retrospective. delay is how long each frame shows,
+// in hundredths of a second as in image/gif; the last frame holds ten
+// times as long before the animation loops.
+func Animation(points []cnnfag.Point, width, delay int) *gif.GIF {
+	anim := &gif.GIF{}
+	pal := animationPalette()
+	index := map[color.RGBA]uint8{}
+	for i, p := range points {
+		frame := gauge(p.Score, p.Rating, p.Date.Format("2006-01-02"), width)
+		anim.Image = append(anim.Image, quantize(frame, pal, index))
+		d := delay
+		if i == len(points)-1 {
+			d *= 10
+		}
+		anim.Delay = append(anim.Delay, d)
+	}
+	return anim
+}
+
+// animationPalette holds every color a gauge frame uses: the fixed colors,
+// each band's full and faded color, and the blends antialiasing makes of
+// them with the background and with the needle.
+func animationPalette() color.Palette {
+	bases := []color.RGBA{ink, muted}
+	for _, band := range cnnfag.Bands {
+		c := bandColor(band.Rating)
+		bases = append(bases, c, tint(c, 0.6))
+	}
+
+	pal := color.Palette{background}
+	seen := map[color.RGBA]bool{background: true}
+	add := func(c color.RGBA) {
+		if !seen[c] && len(pal) < 256 {
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	for _, b := range bases {
+		add(b)
+	}
+	for _, b := range bases {
+		for i := 1; i < 8; i++ {
+			add(tint(b, float64(i)/8))
+		}
+	}
+	for _, b := range bases[1:] {
+		for i := 1; i < 4; i++ {
+			add(mix(ink, b, float64(i)/4))
+		}
+	}
+	return pal
+}
+
+// quantize maps img to the nearest colors of pal. index remembers the
+// mapping across frames, which share almost all their colors.
+func quantize(img *image.RGBA, pal color.Palette, index map[color.RGBA]uint8) *image.Paletted {
+	out := image.NewPaletted(img.Bounds(), pal)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			i, ok := index[c]
+			if !ok {
+				i = uint8(pal.Index(c))
+				index[c] = i
+			}
+			out.SetColorIndex(x, y, i)
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestAnimation(t *testing.T) {
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	var points []cnnfag.Point
+	for i, s := range []float64{10, 50, 90} {
+		points = append(points, cnnfag.Point{Date: day.AddDate(0, 0, i), Score: s, Rating: cnnfag.RatingOf(s)})
+	}
+
+	anim := Animation(points, 200, 5)
+	if len(anim.Image) != 3 {
+		t.Fatalf("got %d frames, want 3", len(anim.Image))
+	}
+	if want := []int{5, 5, 50}; anim.Delay[0] != want[0] || anim.Delay[2] != want[2] {
+		t.Errorf("delays = %v, want %v", anim.Delay, want)
+	}
+
+	// Each frame shows its rating's band in full color.
+	for i, p := range points {
+		frame := anim.Image[i]
+		want := BandColors[p.Rating]
+		found := false
+		for y := frame.Rect.Min.Y; y < frame.Rect.Max.Y && !found; y++ {
+			for x := frame.Rect.Min.X; x < frame.Rect.Max.X && !found; x++ {
+				found = frame.At(x, y) == want
+			}
+		}
+		if !found {
+			t.Errorf("frame %d has no %s", i, p.Rating)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gif.DecodeAll(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnimationPalette(t *testing.T) {
+	pal := animationPalette()
+	if len(pal) > 256 {
+		t.Fatalf("palette has %d colors", len(pal))
+	}
+	for _, c := range []any{background, ink, muted, BandColors["greed"], tint(BandColors["fear"], 0.6)} {
+		found := false
+		for _, p := range pal {
+			found = found || p == c
+		}
+		if !found {
+			t.Errorf("palette lacks %v", c)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package render
+
+import (
+	"image"
+	"math"
+	"strconv"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Chart draws series as a line chart. With bands, as for the index, the
+// scale is 0-100 and CNN's rating bands are shaded behind the line; without,
+// as for an indicator's raw values, the scale fits the data. The latest
+// value is marked with a dot in the color of its rating.
+func Chart(series []cnnfag.Value, width, height int, bands bool) *image.RGBA {
+	img := newImage(width, height)
+	plot(img, img.Bounds(), series, bands, max(1, min(width, height)/200))
+	return img
+}
+
+// What the small multiples call each series, by cnnfag.IndicatorIDs.
+var titles = map[string]string{
+	"index":      "Fear & Greed",
+	"momentum":   "Market momentum",
+	"strength":   "Price strength",
+	"breadth":    "Price breadth",
+	"put_call":   "Put/call options",
+	"vix":        "Volatility",
+	"junk_bond":  "Junk bond demand",
+	"safe_haven": "Safe haven demand",
+}
+
+// Indicators draws small multiples: the index and each of the seven
+// indicators in a grid of four columns and two rows, each titled with its
+// score and rating and charted like Chart.
+func Indicators(res cnnfag.Result, width, height int) *image.RGBA {
+	img := newImage(width, height)
+	scale := max(1, width/600)
+	ids := append([]string{"index"}, cnnfag.IndicatorIDs...)
+	const cols, rows = 4, 2
+
+	for i, id := range ids {
+		col, row := i%cols, i/cols
+		cell := image.Rect(col*width/cols, row*height/rows, (col+1)*width/cols, (row+1)*height/rows)
+
+		score, rating := res.Score, res.Rating
+		if ind, ok := res.Indicator(id); ok {
+			score, rating = ind.Score, ind.Rating
+		}
+		title := titles[id]
+		value := strconv.FormatFloat(score, 'f', 0, 64) + " " + rating
+		x, y := cell.Min.X+2*scale, cell.Min.Y+2*scale
+		drawText(img, x, y, title, scale, ink)
+		drawText(img, x+textWidth(title+" ", scale)+scale, y, value, scale, bandColor(rating))
+
+		series, _ := res.Series(id)
+		cell.Min.Y += textHeight(scale) + 2*scale
+		plot(img, cell, series, id == "index", scale)
+	}
+	return img
+}
+
+// plot charts series inside r: value labels on the left, dates below.
+func plot(img *image.RGBA, r image.Rectangle, series []cnnfag.Value, bands bool, scale int) {
+	lo, hi, ticks, format := axis(series, bands)
+
+	labelWidth := 0
+	for _, t := range ticks {
+		labelWidth = max(labelWidth, textWidth(format(t), scale))
+	}
+	pad := 3 * scale
+	area := image.Rect(r.Min.X+pad+labelWidth+pad, r.Min.Y+pad+textHeight(scale)/2,
+		r.Max.X-4*scale-pad, r.Max.Y-pad-textHeight(scale)-pad)
+	if area.Dx() < 2 || area.Dy() < 2 {
+		return
+	}
+	yOf := func(v float64) float64 {
+		return float64(area.Min.Y) + (hi-v)/(hi-lo)*float64(area.Dy()-1)
+	}
+
+	if bands {
+		for _, band := range cnnfag.Bands {
+			fill(img, image.Rect(area.Min.X, int(yOf(band.Max)), area.Max.X, int(yOf(band.Min))+1), tint(bandColor(band.Rating), 0.8))
+		}
+	}
+	for _, t := range ticks {
+		y := int(math.Round(yOf(t)))
+		if !bands {
+			fill(img, image.Rect(area.Min.X, y, area.Max.X, y+1), gridColor)
+		}
+		label := format(t)
+		drawText(img, area.Min.X-pad-textWidth(label, scale), y-textHeight(scale)/2, label, scale, muted)
+	}
+	fill(img, image.Rect(area.Min.X-1, area.Min.Y, area.Min.X, area.Max.Y), muted)
+	fill(img, image.Rect(area.Min.X-1, area.Max.Y, area.Max.X, area.Max.Y+1), muted)
+
+	if len(series) == 0 {
+		return
+	}
+	xOf := func(i int) float64 {
+		if len(series) == 1 {
+			return float64(area.Max.X - 1)
+		}
+		return float64(area.Min.X) + float64(i)*float64(area.Dx()-1)/float64(len(series)-1)
+	}
+	width := float64(scale) + 0.5
+	for i := 1; i < len(series); i++ {
+		line(img, xOf(i-1), yOf(series[i-1].Value), xOf(i), yOf(series[i].Value), width, ink)
+	}
+	last := series[len(series)-1]
+	x, y := xOf(len(series)-1), yOf(last.Value)
+	disc(img, x, y, 3*float64(scale)+1, ink)
+	disc(img, x, y, 3*float64(scale), bandColor(last.Rating))
+
+	dateY := area.Max.Y + pad
+	first, end := series[0].Date.Format("2006-01-02"), last.Date.Format("2006-01-02")
+	drawText(img, area.Min.X, dateY, first, scale, muted)
+	if len(series) > 1 && textWidth(first+"  "+end, scale) <= area.Dx() {
+		drawText(img, area.Max.X-textWidth(end, scale), dateY, end, scale, muted)
+	}
+}
+
+// axis returns the value range of a chart of series and the values to label
+// on it: 0-100 by the quarter with bands, the data's minimum, middle and
+// maximum without, padded so the line clears the edges.
+func axis(series []cnnfag.Value, bands bool) (lo, hi float64, ticks []float64, format func(float64) string) {
+	if bands || len(series) == 0 {
+		return 0, 100, []float64{0, 25, 50, 75, 100}, func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range series {
+		lo, hi = math.Min(lo, v.Value), math.Max(hi, v.Value)
+	}
+	ticks = []float64{lo, (lo + hi) / 2, hi}
+	if lo == hi {
+		ticks = ticks[:1]
+	}
+	pad := math.Max((hi-lo)*0.05, math.Abs(hi)*0.01+1e-9)
+	return lo - pad, hi + pad, ticks, formatValue
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func testSeries(values ...float64) []cnnfag.Value {
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	var series []cnnfag.Value
+	for i, v := range values {
+		series = append(series, cnnfag.Value{Date: day.AddDate(0, 0, i), Value: v, Rating: cnnfag.RatingOf(v)})
+	}
+	return series
+}
+
+func TestChart(t *testing.T) {
+	img := Chart(testSeries(20, 40, 60, 80, 64), 400, 200, true)
+	if b := img.Bounds(); b.Dx() != 400 || b.Dy() != 200 {
+		t.Fatalf("chart is %v, want 400x200", b)
+	}
+
+	// Every band is shaded somewhere along the plot's right-hand column
+	// of pixels; the line's dot at 64 covers only part of it.
+	seen := map[string]bool{}
+	for y := 0; y < 200; y++ {
+		for _, band := range cnnfag.Bands {
+			if img.RGBAAt(360, y) == tint(BandColors[band.Rating], 0.8) {
+				seen[band.Rating] = true
+			}
+		}
+	}
+	for _, band := range cnnfag.Bands {
+		if !seen[band.Rating] {
+			t.Errorf("no %s shading", band.Rating)
+		}
+	}
+
+	// The line is drawn in ink.
+	inked := 0
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			if img.RGBAAt(x, y) == ink {
+				inked++
+			}
+		}
+	}
+	if inked < 100 {
+		t.Errorf("only %d ink pixels", inked)
+	}
+
+	// Raw series are not shaded; empty and flat ones don't panic.
+	raw := Chart(testSeries(1.5, 1.5), 400, 200, false)
+	for y := 0; y < 200; y++ {
+		for _, band := range cnnfag.Bands {
+			if raw.RGBAAt(360, y) == tint(BandColors[band.Rating], 0.8) {
+				t.Fatalf("raw chart has %s shading", band.Rating)
+			}
+		}
+	}
+	Chart(nil, 400, 200, false)
+	Chart(testSeries(50), 10, 10, true)
+}
+
+func TestIndicators(t *testing.T) {
+	res := cnnfag.Result{Score: 64, Rating: "greed", History: []cnnfag.Point{{Date: time.Now(), Score: 64, Rating: "greed"}}}
+	res.MarketVolatility = cnnfag.Indicator{Score: 30, Rating: "fear", History: testSeries(16, 15, 14)}
+
+	img := Indicators(res, 1200, 600)
+	if b := img.Bounds(); b.Dx() != 1200 || b.Dy() != 600 {
+		t.Fatalf("indicators is %v, want 1200x600", b)
+	}
+	// Each title's rating is printed in its color.
+	for _, rating := range []string{"greed", "fear"} {
+		found := false
+		for y := 0; y < 600 && !found; y++ {
+			for x := 0; x < 1200 && !found; x++ {
+				found = img.RGBAAt(x, y) == BandColors[rating]
+			}
+		}
+		if !found {
+			t.Errorf("no %s title", rating)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// glyphs is a 3x5 pixel font, each glyph five rows of three bits from the
+// top left. It covers what the images print: digits, capitals and a little
+// punctuation. Text is upper-cased before drawing and unknown characters
+// print as blanks.
+var glyphs = map[rune][5]string{
+	'0': {"111", "101", "101", "101", "111"},
+	'1': {"010", "110", "010", "010", "111"},
+	'2': {"111", "001", "111", "100", "111"},
+	'3': {"111", "001", "011", "001", "111"},
+	'4': {"101", "101", "111", "001", "001"},
+	'5': {"111", "100", "111", "001", "111"},
+	'6': {"111", "100", "111", "101", "111"},
+	'7': {"111", "001", "010", "010", "010"},
+	'8': {"111", "101", "111", "101", "111"},
+	'9': {"111", "101", "111", "001", "111"},
+	'A': {"010", "101", "111", "101", "101"},
+	'B': {"110", "101", "110", "101", "110"},
+	'C': {"011", "100", "100", "100", "011"},
+	'D': {"110", "101", "101", "101", "110"},
+	'E': {"111", "100", "110", "100", "111"},
+	'F': {"111", "100", "110", "100", "100"},
+	'G': {"011", "100", "101", "101", "011"},
+	'H': {"101", "101", "111", "101", "101"},
+	'I': {"111", "010", "010", "010", "111"},
+	'J': {"001", "001", "001", "101", "010"},
+	'K': {"101", "101", "110", "101", "101"},
+	'L': {"100", "100", "100", "100", "111"},
+	'M': {"101", "111", "111", "101", "101"},
+	'N': {"110", "101", "101", "101", "101"},
+	'O': {"010", "101", "101", "101", "010"},
+	'P': {"110", "101", "110", "100", "100"},
+	'Q': {"010", "101", "101", "110", "011"},
+	'R': {"110", "101", "110", "101", "101"},
+	'S': {"011", "100", "010", "001", "110"},
+	'T': {"111", "010", "010", "010", "010"},
+	'U': {"101", "101", "101", "101", "111"},
+	'V': {"101", "101", "101", "101", "010"},
+	'W': {"101", "101", "111", "111", "101"},
+	'X': {"101", "101", "010", "101", "101"},
+	'Y': {"101", "101", "010", "010", "010"},
+	'Z': {"111", "001", "010", "100", "111"},
+	'.': {"000", "000", "000", "000", "010"},
+	',': {"000", "000", "000", "010", "100"},
+	'-': {"000", "000", "111", "000", "000"},
+	'+': {"000", "010", "111", "010", "000"},
+	':': {"000", "010", "000", "010", "000"},
+	'/': {"001", "001", "010", "100", "100"},
+	'%': {"101", "001", "010", "100", "101"},
+	'&': {"010", "101", "010", "101", "011"},
+	'(': {"010", "100", "100", "100", "010"},
+	')': {"010", "001", "001", "001", "010"},
+}
+
+// textWidth is the width in pixels of s drawn at scale: four columns per
+// character, including the gap, less the trailing gap.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (4*n - 1) * scale
+}
+
+// textHeight is the height in pixels of a line drawn at scale.
+func textHeight(scale int) int { return 5 * scale }
+
+// drawText draws s with its top left corner at x, y, each font pixel a
+// scale-sized square.
+func drawText(img *image.RGBA, x, y int, s string, scale int, c color.RGBA) {
+	for _, r := range strings.ToUpper(s) {
+		g, ok := glyphs[r]
+		if ok {
+			for row, bits := range g {
+				for col, bit := range bits {
+					if bit == '1' {
+						fill(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+					}
+				}
+			}
+		}
+		x += 4 * scale
+	}
+}
+
+// drawTextCentered draws s centered horizontally on x.
+func drawTextCentered(img *image.RGBA, x, y int, s string, scale int, c color.RGBA) {
+	drawText(img, x-textWidth(s, scale)/2, y, s, scale, c)
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"image"
+	"testing"
+)
+
+func TestGlyphs(t *testing.T) {
+	for r, g := range glyphs {
+		for _, row := range g {
+			if len(row) != 3 {
+				t.Errorf("glyph %q has a row %q, want 3 columns", r, row)
+			}
+		}
+	}
+}
+
+func TestDrawText(t *testing.T) {
+	if got, want := textWidth("64", 2), 14; got != want {
+		t.Errorf("textWidth(64, 2) = %d, want %d", got, want)
+	}
+
+	img := newImage(20, 10)
+	drawText(img, 0, 0, "-", 2, ink)
+	// The dash is the middle row, 6 pixels wide at scale 2.
+	for x := 0; x < 8; x++ {
+		want := background
+		if x < 6 {
+			want = ink
+		}
+		if got := img.RGBAAt(x, 4); got != want {
+			t.Errorf("pixel %d,4 = %v, want %v", x, got, want)
+		}
+	}
+	if img.RGBAAt(0, 0) != background {
+		t.Error("dash reaches the top row")
+	}
+
+	// Lower case is drawn as capitals, unknown runes as blanks.
+	a, b := newImage(20, 10), newImage(20, 10)
+	drawText(a, 0, 0, "a~", 1, ink)
+	drawText(b, 0, 0, "A", 1, ink)
+	if !equal(a, b) {
+		t.Error(`"a~" draws differently from "A"`)
+	}
+}
+
+func equal(a, b *image.RGBA) bool {
+	return string(a.Pix) == string(b.Pix)
+}
@@ -0,0 +1,85 @@
+package render
+
+import (
+	"image"
+	"math"
+	"strconv"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// Gauge draws res as a semicircular gauge width pixels wide: the five rating
+// bands as an arc, the band of the current rating in full color and the
+// others faded, a needle at the score, and below it the score, the rating
+// and the update time. The height follows from the width.
+func Gauge(res cnnfag.Result, width int) *image.RGBA {
+	caption := ""
+	if !res.Timestamp.IsZero() {
+		caption = res.Timestamp.UTC().Format("2006-01-02 15:04 UTC")
+	}
+	return gauge(res.Score, res.Rating, caption, width)
+}
+
+// gauge draws the gauge with caption, if any, under the rating.
+func gauge(score float64, rating, caption string, width int) *image.RGBA {
+	w := float64(width)
+	big := max(1, width/75)
+	medium, small := max(1, big/2), max(1, big/3)
+
+	margin := w / 30
+	outer := w * 0.4
+	inner := outer * 0.68
+	cx, cy := w/2, margin+outer+float64(textHeight(small))
+
+	y := int(cy + margin)
+	scoreY := y
+	y += textHeight(big) + 2*big
+	ratingY := y
+	y += textHeight(medium) + 2*big
+	timeY := y
+	y += textHeight(small) + int(margin)
+
+	img := newImage(width, y)
+
+	// The arc, one band at a time, with a small gap between bands.
+	gap := math.Max(1, w/300)
+	for y := int(cy - outer - 1); y <= int(cy+1); y++ {
+		for x := int(cx - outer - 1); x <= int(cx+outer+1); x++ {
+			dx, dy := float64(x)+0.5-cx, cy-(float64(y)+0.5)
+			r := math.Hypot(dx, dy)
+			edge := math.Max(math.Max(inner-r, r-outer), -dy)
+			theta := math.Max(0, math.Min(math.Pi, math.Atan2(dy, dx)))
+			for _, band := range cnnfag.Bands[1:] {
+				d := math.Abs(theta-math.Pi*(1-band.Min/100)) * r
+				edge = math.Max(edge, gap/2-d)
+			}
+			zone := cnnfag.RatingOf(100 * (1 - theta/math.Pi))
+			c := bandColor(zone)
+			if zone != rating {
+				c = tint(c, 0.6)
+			}
+			blend(img, x, y, c, coverage(edge))
+		}
+	}
+
+	// Scale labels around the outside of the arc.
+	for _, v := range []float64{0, 25, 50, 75, 100} {
+		theta := math.Pi * (1 - v/100)
+		r := outer + float64(textHeight(small))
+		lx, ly := cx+math.Cos(theta)*r, cy-math.Sin(theta)*r
+		label := strconv.FormatFloat(v, 'f', 0, 64)
+		drawText(img, int(lx)-textWidth(label, small)/2, int(ly)-textHeight(small)/2, label, small, muted)
+	}
+
+	// The needle.
+	theta := math.Pi * (1 - math.Max(0, math.Min(100, score))/100)
+	tip := inner - w/60
+	line(img, cx, cy, cx+math.Cos(theta)*tip, cy-math.Sin(theta)*tip, math.Max(2, w/90), ink)
+	disc(img, cx, cy, w/35, ink)
+	disc(img, cx, cy, w/110, background)
+
+	drawTextCentered(img, width/2, scoreY, strconv.FormatFloat(score, 'f', 0, 64), big, bandColor(rating))
+	drawTextCentered(img, width/2, ratingY, rating, medium, ink)
+	drawTextCentered(img, width/2, timeY, caption, small, muted)
+	return img
+}
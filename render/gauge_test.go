@@ -0,0 +1,55 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+func TestGauge(t *testing.T) {
+	res := cnnfag.Result{Score: 64, Rating: "greed", Timestamp: time.Date(2026, 8, 13, 20, 0, 0, 0, time.UTC)}
+	img := Gauge(res, 600)
+
+	if b := img.Bounds(); b.Dx() != 600 || b.Dy() < 300 || b.Dy() > 600 {
+		t.Fatalf("gauge is %v, want 600 wide and about half as high", b)
+	}
+
+	// The middle of each band's arc is its color, full for the current
+	// rating and faded for the others.
+	w := 600.0
+	cx, cy := w/2, w/30+w*0.4+float64(textHeight(2))
+	r := w * 0.4 * 0.84
+	for _, band := range cnnfag.Bands {
+		theta := math.Pi * (1 - (band.Min+band.Max)/200)
+		x, y := int(cx+math.Cos(theta)*r), int(cy-math.Sin(theta)*r)
+		want := BandColors[band.Rating]
+		if band.Rating != res.Rating {
+			want = tint(want, 0.6)
+		}
+		if got := img.RGBAAt(x, y); got != want {
+			t.Errorf("%s band at %d,%d is %v, want %v", band.Rating, x, y, got, want)
+		}
+	}
+	// The hub of the needle.
+	if got := img.RGBAAt(int(cx+w/60), int(cy)); got != ink {
+		t.Errorf("needle hub is %v, want %v", got, ink)
+	}
+
+	if err := png.Encode(new(bytes.Buffer), img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBlend(t *testing.T) {
+	img := newImage(1, 1)
+	blend(img, 0, 0, color.RGBA{0, 0, 0, 0xff}, 0.5)
+	if got, want := img.RGBAAt(0, 0), (color.RGBA{0x80, 0x80, 0x80, 0xff}); got != want {
+		t.Errorf("half coverage of black on white = %v, want %v", got, want)
+	}
+	blend(img, 5, 5, ink, 1) // outside the image: no panic
+}
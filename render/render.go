@@ -0,0 +1,126 @@
+// Package render draws the Fear & Greed Index as images, using only the
+// standard library's image packages: a gauge like the needle image CNN used
+// to publish, a line chart of the history over the rating bands, and small
+// multiples of the seven indicators. The images are *image.RGBA, ready for
+// image/png:
+//
+//	res, err := cnnfag.Get(ctx)
+//	...
+//	err = png.Encode(f, render.Gauge(res, 600))
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+)
+
+// BandColors are the colors of CNN's rating bands, by rating. Replace entries
+// to restyle every image.
+var BandColors = map[string]color.RGBA{
+	"extreme fear":  {0xc6, 0x28, 0x28, 0xff},
+	"fear":          {0xef, 0x6c, 0x4a, 0xff},
+	"neutral":       {0xf9, 0xa8, 0x25, 0xff},
+	"greed":         {0x7c, 0xb3, 0x42, 0xff},
+	"extreme greed": {0x2e, 0x7d, 0x32, 0xff},
+}
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	ink        = color.RGBA{0x26, 0x32, 0x38, 0xff}
+	muted      = color.RGBA{0x78, 0x90, 0x9c, 0xff}
+	gridColor  = color.RGBA{0xe6, 0xe9, 0xeb, 0xff}
+)
+
+// bandColor is the color of rating, or ink for an unknown rating.
+func bandColor(rating string) color.RGBA {
+	if c, ok := BandColors[rating]; ok {
+		return c
+	}
+	return ink
+}
+
+func newImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, img.Bounds(), background)
+	return img
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// tint mixes c with white, f being the share of white.
+func tint(c color.RGBA, f float64) color.RGBA {
+	return mix(c, color.RGBA{0xff, 0xff, 0xff, 0xff}, f)
+}
+
+// mix blends a towards b by f.
+func mix(a, b color.RGBA, f float64) color.RGBA {
+	m := func(x, y uint8) uint8 { return uint8(float64(x)*(1-f) + float64(y)*f + 0.5) }
+	return color.RGBA{m(a.R, b.R), m(a.G, b.G), m(a.B, b.B), 0xff}
+}
+
+// blend paints the opaque color c over the pixel at x, y with coverage a,
+// clipped to [0, 1].
+func blend(img *image.RGBA, x, y int, c color.RGBA, a float64) {
+	if a <= 0 || !image.Pt(x, y).In(img.Rect) {
+		return
+	}
+	a = math.Min(a, 1)
+	p := img.Pix[img.PixOffset(x, y):]
+	p[0] = uint8(float64(p[0])*(1-a) + float64(c.R)*a + 0.5)
+	p[1] = uint8(float64(p[1])*(1-a) + float64(c.G)*a + 0.5)
+	p[2] = uint8(float64(p[2])*(1-a) + float64(c.B)*a + 0.5)
+	p[3] = uint8(float64(p[3])*(1-a) + 255*a + 0.5)
+}
+
+// coverage turns the distance of a pixel's center outside a shape's edge,
+// negative inside, into the share of the pixel the shape covers. It is what
+// keeps the edges of the gauge and the chart lines from looking jagged.
+func coverage(d float64) float64 {
+	return math.Max(0, math.Min(1, 0.5-d))
+}
+
+// disc draws a filled circle.
+func disc(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for y := int(cy - r - 1); y <= int(cy+r+1); y++ {
+		for x := int(cx - r - 1); x <= int(cx+r+1); x++ {
+			d := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			blend(img, x, y, c, coverage(d-r))
+		}
+	}
+}
+
+// line draws a segment width pixels thick with round ends.
+func line(img *image.RGBA, x0, y0, x1, y1, width float64, c color.RGBA) {
+	pad := width/2 + 1
+	dx, dy := x1-x0, y1-y0
+	length2 := dx*dx + dy*dy
+	for y := int(math.Min(y0, y1) - pad); y <= int(math.Max(y0, y1)+pad); y++ {
+		for x := int(math.Min(x0, x1) - pad); x <= int(math.Max(x0, x1)+pad); x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			t := 0.0
+			if length2 > 0 {
+				t = math.Max(0, math.Min(1, ((px-x0)*dx+(py-y0)*dy)/length2))
+			}
+			d := math.Hypot(px-(x0+t*dx), py-(y0+t*dy))
+			blend(img, x, y, c, coverage(d-width/2))
+		}
+	}
+}
+
+// formatValue prints a value with a precision that suits its size: an S&P
+// level needs no decimals, a put/call ratio needs a few.
+func formatValue(v float64) string {
+	switch a := math.Abs(v); {
+	case a >= 1000:
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	case a >= 10:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	default:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+}
@@ -0,0 +1,201 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"math"
+	"strconv"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+const svgFont = "Verdana,Geneva,DejaVu Sans,sans-serif"
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// num prints a coordinate with at most two decimals, which keeps the SVG
+// small and stable across platforms.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// GaugeSVG is Gauge as SVG: the same arc, needle and text, scalable to any
+// size. width sets the coordinate system and the default display size.
+func GaugeSVG(res cnnfag.Result, width int) []byte {
+	w := float64(width)
+	outer := w * 0.4
+	inner := outer * 0.68
+	small, medium, big := w/40, w/20, w/8
+	cx, cy := w/2, w/30+outer+small*1.5
+	scoreY := cy + w/30 + big*0.8
+	ratingY := scoreY + medium*1.6
+	timeY := ratingY + small*2
+	height := timeY + w/30
+
+	pt := func(score, r float64) (string, string) {
+		theta := math.Pi * (1 - score/100)
+		return num(cx + math.Cos(theta)*r), num(cy - math.Sin(theta)*r)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%s" viewBox="0 0 %d %s" role="img" aria-label="Fear &amp; Greed Index: %.0f %s">`+"\n",
+		width, num(height), width, num(height), res.Score, html.EscapeString(res.Rating))
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	for _, band := range cnnfag.Bands {
+		c := bandColor(band.Rating)
+		if band.Rating != res.Rating {
+			c = tint(c, 0.6)
+		}
+		x0, y0 := pt(band.Min, outer)
+		x1, y1 := pt(band.Max, outer)
+		x2, y2 := pt(band.Max, inner)
+		x3, y3 := pt(band.Min, inner)
+		fmt.Fprintf(&b, `<path d="M%s %s A%s %s 0 0 1 %s %s L%s %s A%s %s 0 0 0 %s %s Z" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+			x0, y0, num(outer), num(outer), x1, y1, x2, y2, num(inner), num(inner), x3, y3, hex(c), hex(background), num(math.Max(1, w/300)))
+	}
+	for _, v := range []float64{0, 25, 50, 75, 100} {
+		x, y := pt(v, outer+small)
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="%s" font-size="%s" fill="%s" text-anchor="middle" dominant-baseline="middle">%.0f</text>`+"\n",
+			x, y, svgFont, num(small), hex(muted), v)
+	}
+
+	tx, ty := pt(math.Max(0, math.Min(100, res.Score)), inner-w/60)
+	fmt.Fprintf(&b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s" stroke-linecap="round"/>`+"\n",
+		num(cx), num(cy), tx, ty, hex(ink), num(math.Max(2, w/90)))
+	fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(cx), num(cy), num(w/35), hex(ink))
+	fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(cx), num(cy), num(w/110), hex(background))
+
+	text := func(y, size float64, c color.RGBA, weight, s string) {
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="%s" font-size="%s" font-weight="%s" fill="%s" text-anchor="middle">%s</text>`+"\n",
+			num(cx), num(y), svgFont, num(size), weight, hex(c), html.EscapeString(s))
+	}
+	text(scoreY, big, bandColor(res.Rating), "bold", strconv.FormatFloat(res.Score, 'f', 0, 64))
+	text(ratingY, medium, ink, "normal", res.Rating)
+	if !res.Timestamp.IsZero() {
+		text(timeY, small, muted, "normal", res.Timestamp.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// ChartSVG is Chart as SVG: a line chart of series over the shaded rating
+// bands when bands is set, over a fitted scale when not.
+func ChartSVG(series []cnnfag.Value, width, height int, bands bool) []byte {
+	lo, hi, ticks, format := axis(series, bands)
+
+	const size = 11.0
+	labelWidth := 0.0
+	for _, t := range ticks {
+		labelWidth = math.Max(labelWidth, textLength(format(t), size))
+	}
+	left, top := labelWidth+10, size
+	right, bottom := float64(width)-10, float64(height)-size*2
+	xOf := func(i int) float64 {
+		if len(series) == 1 {
+			return right
+		}
+		return left + float64(i)*(right-left)/float64(len(series)-1)
+	}
+	yOf := func(v float64) float64 { return top + (hi-v)/(hi-lo)*(bottom-top) }
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	if bands {
+		for _, band := range cnnfag.Bands {
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+				num(left), num(yOf(band.Max)), num(right-left), num(yOf(band.Min)-yOf(band.Max)), hex(tint(bandColor(band.Rating), 0.8)))
+		}
+	}
+	for _, t := range ticks {
+		y := num(yOf(t))
+		if !bands {
+			fmt.Fprintf(&b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n", num(left), y, num(right), y, hex(gridColor))
+		}
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="%s" font-size="%s" fill="%s" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n",
+			num(left-5), y, svgFont, num(size), hex(muted), format(t))
+	}
+	fmt.Fprintf(&b, `<path d="M%s %s V%s H%s" fill="none" stroke="%s"/>`+"\n", num(left), num(top), num(bottom), num(right), hex(muted))
+
+	if len(series) > 0 {
+		b.WriteString(`<polyline points="`)
+		for i, v := range series {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "%s,%s", num(xOf(i)), num(yOf(v.Value)))
+		}
+		fmt.Fprintf(&b, `" fill="none" stroke="%s" stroke-width="1.5" stroke-linejoin="round"/>`+"\n", hex(ink))
+
+		last := series[len(series)-1]
+		fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="4" fill="%s" stroke="%s"><title>%s %s (%s)</title></circle>`+"\n",
+			num(xOf(len(series)-1)), num(yOf(last.Value)), hex(bandColor(last.Rating)), hex(ink),
+			last.Date.Format("2006-01-02"), format(last.Value), html.EscapeString(last.Rating))
+
+		dateY := num(bottom + size*1.5)
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="%s" font-size="%s" fill="%s">%s</text>`+"\n",
+			num(left), dateY, svgFont, num(size), hex(muted), series[0].Date.Format("2006-01-02"))
+		if len(series) > 1 {
+			fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="%s" font-size="%s" fill="%s" text-anchor="end">%s</text>`+"\n",
+				num(right), dateY, svgFont, num(size), hex(muted), last.Date.Format("2006-01-02"))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// Badge returns a shields.io-style badge: label on gray, then the score and
+// rating on the rating's color, for example "fear & greed | 64 greed".
+func Badge(label string, score float64, rating string) []byte {
+	value := strconv.FormatFloat(score, 'f', 0, 64) + " " + rating
+	const size, pad = 11.0, 6.0
+	lw := math.Round(textLength(label, size) + 2*pad)
+	vw := math.Round(textLength(value, size) + 2*pad)
+	w := lw + vw
+	title := html.EscapeString(label + ": " + value)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="20" role="img" aria-label="%s">`+"\n", num(w), title)
+	fmt.Fprintf(&b, "<title>%s</title>\n", title)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%s" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", num(w))
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%s" height="20" fill="#555"/><rect x="%s" width="%s" height="20" fill="%s"/><rect width="%s" height="20" fill="url(#s)"/></g>`+"\n",
+		num(lw), num(lw), num(vw), hex(bandColor(rating)), num(w))
+	fmt.Fprintf(&b, `<g fill="#fff" text-anchor="middle" font-family="%s" font-size="%s">`+"\n", svgFont, num(size))
+	for _, t := range []struct {
+		x float64
+		s string
+	}{{lw / 2, label}, {lw + vw/2, value}} {
+		s := html.EscapeString(t.s)
+		fmt.Fprintf(&b, `<text x="%s" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%s" y="14">%s</text>`+"\n", num(t.x), s, num(t.x), s)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.Bytes()
+}
+
+// textLength estimates the width of s in Verdana at size, close enough to
+// size badges the way shields.io does without measuring a real font.
+func textLength(s string, size float64) float64 {
+	em := 0.0
+	for _, r := range s {
+		switch {
+		case r == ' ':
+			em += 0.35
+		case r == 'i' || r == 'l' || r == 'j' || r == '.' || r == ',' || r == ':' || r == '|' || r == '\'':
+			em += 0.3
+		case r == 'f' || r == 't' || r == 'r' || r == 'I':
+			em += 0.42
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W':
+			em += 0.95
+		case r >= 'A' && r <= 'Z' || r == '&' || r == '%':
+			em += 0.7
+		default:
+			em += 0.62
+		}
+	}
+	return em * size
+}
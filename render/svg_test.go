@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	cnnfag "github.com/wildsurfer/cnn-fear-and-greed-parse/v2"
+)
+
+// wellFormed fails the test if svg does not parse as XML, and returns its
+// character data.
+func wellFormed(t *testing.T, svg []byte) string {
+	t.Helper()
+	var text strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return text.String()
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		if cd, ok := tok.(xml.CharData); ok {
+			text.Write(cd)
+		}
+	}
+}
+
+func TestGaugeSVG(t *testing.T) {
+	res := cnnfag.Result{Score: 64.37, Rating: "greed", Timestamp: time.Date(2026, 8, 13, 20, 0, 0, 0, time.UTC)}
+	svg := GaugeSVG(res, 600)
+	text := wellFormed(t, svg)
+
+	for _, want := range []string{"64", "greed", "2026-08-13 20:00 UTC"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("gauge text lacks %q", want)
+		}
+	}
+	if n := bytes.Count(svg, []byte("<path ")); n != len(cnnfag.Bands) {
+		t.Errorf("gauge has %d arcs, want %d", n, len(cnnfag.Bands))
+	}
+	if !bytes.Contains(svg, []byte(`fill="`+hex(BandColors["greed"])+`"`)) {
+		t.Error("the current band is not in full color")
+	}
+	if !bytes.Contains(svg, []byte(`width="600"`)) {
+		t.Error("gauge is not 600 wide")
+	}
+}
+
+func TestChartSVG(t *testing.T) {
+	svg := ChartSVG(testSeries(20, 40, 60, 80, 64), 400, 200, true)
+	text := wellFormed(t, svg)
+	for _, want := range []string{"2026-01-05", "2026-01-09", "64 (greed)", "100"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("chart text lacks %q", want)
+		}
+	}
+	for _, band := range cnnfag.Bands {
+		if !bytes.Contains(svg, []byte(hex(tint(BandColors[band.Rating], 0.8)))) {
+			t.Errorf("no %s shading", band.Rating)
+		}
+	}
+	if !bytes.Contains(svg, []byte(`<polyline points="`)) {
+		t.Error("chart has no line")
+	}
+
+	raw := ChartSVG(testSeries(1.5, 1.5), 400, 200, false)
+	wellFormed(t, raw)
+	if bytes.Contains(raw, []byte(hex(tint(BandColors["greed"], 0.8)))) {
+		t.Error("raw chart is shaded")
+	}
+	wellFormed(t, ChartSVG(nil, 400, 200, false))
+}
+
+func TestBadge(t *testing.T) {
+	svg := Badge("fear & greed", 64.37, "greed")
+	text := wellFormed(t, svg)
+	if !strings.Contains(text, "fear & greed") || !strings.Contains(text, "64 greed") {
+		t.Errorf("badge text = %q", text)
+	}
+	if !bytes.Contains(svg, []byte(hex(BandColors["greed"]))) {
+		t.Error("badge is not in the greed color")
+	}
+
+	// A longer value makes a wider badge.
+	wide := Badge("fear & greed", 12, "extreme fear")
+	if len(wide) == 0 || textLength("12 extreme fear", 11) <= textLength("64 greed", 11) {
+		t.Error("textLength does not grow with the text")
+	}
+	wellFormed(t, wide)
+}
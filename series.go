@@ -0,0 +1,92 @@
+package cnnfag
+
+import "time"
+
+// IndicatorIDs are the short IDs Result.Indicator and Result.Series accept,
+// in the order CNN lists the indicators.
+var IndicatorIDs = []string{"momentum", "strength", "breadth", "put_call", "vix", "junk_bond", "safe_haven"}
+
+// Indicator returns the component indicator with the given short ID, one of
+// IndicatorIDs.
+func (r Result) Indicator(id string) (Indicator, bool) {
+	switch id {
+	case "momentum":
+		return r.MarketMomentum, true
+	case "strength":
+		return r.StockPriceStrength, true
+	case "breadth":
+		return r.StockPriceBreadth, true
+	case "put_call":
+		return r.PutCallOptions, true
+	case "vix":
+		return r.MarketVolatility, true
+	case "junk_bond":
+		return r.JunkBondDemand, true
+	case "safe_haven":
+		return r.SafeHavenDemand, true
+	}
+	return Indicator{}, false
+}
+
+// Series returns a daily history, oldest first: the index's for id "index",
+// where each Value holds the score, or the raw series of the indicator with
+// that ID.
+func (r Result) Series(id string) ([]Value, bool) {
+	if id == "index" {
+		values := make([]Value, len(r.History))
+		for i, p := range r.History {
+			values[i] = Value{Date: p.Date, Value: p.Score, Rating: p.Rating}
+		}
+		return values, true
+	}
+	ind, ok := r.Indicator(id)
+	return ind.History, ok
+}
+
+// Between returns the values dated from start through end, inclusive. A
+// zero start or end leaves that side open. values must be oldest first, as
+// Series returns them.
+func Between(values []Value, start, end time.Time) []Value {
+	var out []Value
+	for _, v := range values {
+		if (start.IsZero() || !v.Date.Before(start)) && (end.IsZero() || !v.Date.After(end)) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Period is a resampling period for Resample.
+type Period string
+
+const (
+	Weekly  Period = "weekly"  // ISO weeks, Monday to Sunday
+	Monthly Period = "monthly" // calendar months
+)
+
+// Resample keeps the last value of each period, the weekly or monthly close,
+// dated as it was observed. values must be oldest first. Any other period
+// returns values unchanged.
+func Resample(values []Value, p Period) []Value {
+	var key func(time.Time) [2]int
+	switch p {
+	case Weekly:
+		key = func(t time.Time) [2]int {
+			y, w := t.ISOWeek()
+			return [2]int{y, w}
+		}
+	case Monthly:
+		key = func(t time.Time) [2]int { return [2]int{t.Year(), int(t.Month())} }
+	default:
+		return values
+	}
+
+	var out []Value
+	for i, v := range values {
+		if i+1 < len(values) && key(v.Date) == key(values[i+1].Date) {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package cnnfag
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultSeries(t *testing.T) {
+	res := Result{
+		History:          []Point{{Score: 40, Rating: "fear"}, {Score: 60, Rating: "greed"}},
+		MarketVolatility: Indicator{Score: 50, History: []Value{{Value: 17.5}}},
+	}
+
+	index, ok := res.Series("index")
+	if !ok || len(index) != 2 || index[1].Value != 60 || index[1].Rating != "greed" {
+		t.Errorf(`Series("index") = %v, %v`, index, ok)
+	}
+	vix, ok := res.Series("vix")
+	if !ok || len(vix) != 1 || vix[0].Value != 17.5 {
+		t.Errorf(`Series("vix") = %v, %v`, vix, ok)
+	}
+	if _, ok := res.Series("nope"); ok {
+		t.Error(`Series("nope") found a series`)
+	}
+
+	for _, id := range IndicatorIDs {
+		if _, ok := res.Indicator(id); !ok {
+			t.Errorf("Indicator(%q) not found", id)
+		}
+	}
+	if ind, _ := res.Indicator("vix"); ind.Score != 50 {
+		t.Errorf(`Indicator("vix").Score = %v, want 50`, ind.Score)
+	}
+}
+
+func TestBetweenAndResample(t *testing.T) {
+	// Weekdays from Thursday 2026-01-01 to Tuesday 2026-02-03.
+	var values []Value
+	for d := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); !d.After(time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			values = append(values, Value{Date: d, Value: float64(d.Day())})
+		}
+	}
+	dates := func(vs []Value) string {
+		var s []string
+		for _, v := range vs {
+			s = append(s, v.Date.Format(time.DateOnly))
+		}
+		return strings.Join(s, " ")
+	}
+
+	got := Between(values, time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC))
+	if want := "2026-01-08 2026-01-09 2026-01-12"; dates(got) != want {
+		t.Errorf("Between = %s, want %s", dates(got), want)
+	}
+	if got := Between(values, time.Time{}, time.Time{}); len(got) != len(values) {
+		t.Errorf("open Between kept %d of %d values", len(got), len(values))
+	}
+
+	if got, want := dates(Resample(values, Weekly)), "2026-01-02 2026-01-09 2026-01-16 2026-01-23 2026-01-30 2026-02-03"; got != want {
+		t.Errorf("weekly = %s, want %s", got, want)
+	}
+	if got, want := dates(Resample(values, Monthly)), "2026-01-30 2026-02-03"; got != want {
+		t.Errorf("monthly = %s, want %s", got, want)
+	}
+	if got := Resample(values, "daily"); len(got) != len(values) {
+		t.Errorf("daily resample kept %d of %d values", len(got), len(values))
+	}
+}
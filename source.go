@@ -0,0 +1,146 @@
+package cnnfag
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// var, not const: tests point it at an httptest server.
+var pageURL = "https://www.cnn.com/markets/fear-and-greed"
+
+// Source is one way of fetching the index. Implement it to add a mirror or a
+// cache to the chain in Sources.
+type Source interface {
+	// Name identifies the source in Result.Source and in Get's errors.
+	Name() string
+	Fetch(ctx context.Context) (Result, error)
+}
+
+// Sources is the chain Get walks, in order, for as long as CNN refuses the
+// requests: the JSON endpoint with a Chrome-like request, the same endpoint
+// with Firefox and Safari headers in case CNN starts refusing one browser
+// profile, and last the public page, which only carries the headline numbers
+// (no indicators, no history). Replace it to reorder, trim or extend the
+// chain; a source's error other than a 403 or 418 from CNN ends it.
+var Sources = []Source{
+	endpointSource{name: "graphdata", header: http.Header{
+		"User-Agent": {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"},
+		"Referer":    {"https://www.cnn.com/markets/fear-and-greed"},
+	}},
+	endpointSource{name: "graphdata-firefox", header: http.Header{
+		"User-Agent":      {"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0"},
+		"Referer":         {"https://www.cnn.com/"},
+		"Origin":          {"https://www.cnn.com"},
+		"Accept":          {"application/json, text/plain, */*"},
+		"Accept-Language": {"en-US,en;q=0.5"},
+	}},
+	endpointSource{name: "graphdata-safari", header: http.Header{
+		"User-Agent":      {"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"},
+		"Referer":         {"https://edition.cnn.com/markets/fear-and-greed"},
+		"Accept":          {"*/*"},
+		"Accept-Language": {"en-US,en;q=0.9"},
+	}},
+	pageSource{},
+}
+
+// endpointSource requests the JSON endpoint with one set of browser headers.
+// The endpoint returns 418 unless at least User-Agent and Referer are
+// present.
+type endpointSource struct {
+	name   string
+	header http.Header
+}
+
+func (s endpointSource) Name() string { return s.name }
+
+func (s endpointSource) Fetch(ctx context.Context) (Result, error) {
+	return fetchEndpoint(ctx, s.header)
+}
+
+// pageSource reads the gauge CNN renders into the public page's HTML.
+type pageSource struct{}
+
+func (pageSource) Name() string { return "page" }
+
+var (
+	pageDial      = regexp.MustCompile(`market-fng-gauge__dial-number-value[^>]*>\s*([0-9.]+)\s*<`)
+	pageTimestamp = regexp.MustCompile(`market-fng-gauge__timestamp[^>]*data-timestamp="([^"]+)"`)
+	pageLabel     = regexp.MustCompile(`market-fng-gauge__historical-item-label[^>]*>\s*([^<]+?)\s*<`)
+	pageValue     = regexp.MustCompile(`market-fng-gauge__historical-item-index-value[^>]*>\s*([0-9.]+)\s*<`)
+)
+
+func (pageSource) Fetch(ctx context.Context) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	res, err := HTTPClient.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("fetching fear and greed page: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Result{}, statusError(res.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, 8<<20))
+	if err != nil {
+		return Result{}, fmt.Errorf("reading page: %w", err)
+	}
+
+	return parsePage(body)
+}
+
+// parsePage extracts the score and the past-period values from the page. The
+// page shows whole numbers only, and ratings are derived from the score as
+// CNN's own labels are. A page without its update time is an error: the
+// response's Date would differ on every fetch, and callers compare
+// timestamps to spot new readings.
+func parsePage(body []byte) (Result, error) {
+	m := pageDial.FindSubmatch(body)
+	if m == nil {
+		return Result{}, ErrEmptyResult
+	}
+	score, err := strconv.ParseFloat(string(m[1]), 64)
+	if err != nil {
+		return Result{}, ErrEmptyResult
+	}
+
+	result := Result{Score: score, Rating: RatingOf(score)}
+
+	if m := pageTimestamp.FindSubmatch(body); m != nil {
+		result.Timestamp, _ = time.Parse(time.RFC3339, string(m[1]))
+	}
+	if result.Timestamp.IsZero() {
+		return Result{}, fmt.Errorf("%w: the page shows no update time", ErrEmptyResult)
+	}
+
+	labels := pageLabel.FindAllSubmatch(body, -1)
+	values := pageValue.FindAllSubmatch(body, -1)
+	for i := 0; i < len(labels) && i < len(values); i++ {
+		v, err := strconv.ParseFloat(string(values[i][1]), 64)
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(string(labels[i][1])) {
+		case "previous close":
+			result.PreviousClose = v
+		case "1 week ago":
+			result.OneWeekAgo = v
+		case "1 month ago":
+			result.OneMonthAgo = v
+		case "1 year ago":
+			result.OneYearAgo = v
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package cnnfag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetFallsBackToHeaderProfile(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A CNN that has started refusing Chrome.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("User-Agent"), "Firefox") {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	result, err := Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Source != "graphdata-firefox" {
+		t.Errorf("Source = %q, want %q", result.Source, "graphdata-firefox")
+	}
+	if result.Score != 64.3714285714286 || len(result.History) != 3 {
+		t.Errorf("Score = %v with %d history points, want the fixture", result.Score, len(result.History))
+	}
+}
+
+func TestGetStopsOnOtherErrors(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"server error": func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+		"empty result": func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("{}")) },
+		"not json":     func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("<html>")) },
+	} {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			handler(w, r)
+		}))
+		useServer(t, srv.URL)
+
+		_, err := Get(context.Background())
+		srv.Close()
+		if err == nil {
+			t.Errorf("%s: Get succeeded", name)
+		}
+		// Another source would only hide that CNN changed something.
+		if requests != 1 || strings.Contains(err.Error(), "graphdata-firefox") {
+			t.Errorf("%s: %d requests, err %v; want the chain to stop at the first source", name, requests, err)
+		}
+	}
+}
+
+func TestGetFallsBackToPage(t *testing.T) {
+	page, err := os.ReadFile("testdata/fear-and-greed.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphdata", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(page)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	oldEndpoint, oldPage := endpoint, pageURL
+	endpoint, pageURL = srv.URL+"/graphdata", srv.URL+"/page"
+	defer func() { endpoint, pageURL = oldEndpoint, oldPage }()
+
+	result, err := Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Source != "page" {
+		t.Errorf("Source = %q, want %q", result.Source, "page")
+	}
+	if result.Score != 64 || result.Rating != "greed" {
+		t.Errorf("Score = %v (%s), want 64 (greed)", result.Score, result.Rating)
+	}
+	wantTS := time.Date(2026, 8, 11, 0, 0, 0, 0, time.UTC)
+	if !result.Timestamp.Equal(wantTS) {
+		t.Errorf("Timestamp = %v, want %v", result.Timestamp, wantTS)
+	}
+	if result.PreviousClose != 64 || result.OneWeekAgo != 60 || result.OneMonthAgo != 47 || result.OneYearAgo != 58 {
+		t.Errorf("past values = %v, %v, %v, %v, want 64, 60, 47, 58",
+			result.PreviousClose, result.OneWeekAgo, result.OneMonthAgo, result.OneYearAgo)
+	}
+	if len(result.History) != 0 || result.MarketVolatility.Rating != "" {
+		t.Error("the page has no history or indicators, but the result does")
+	}
+}
+
+func TestParsePageWithoutTimestamp(t *testing.T) {
+	dial := `<span class="market-fng-gauge__dial-number-value">23</span>`
+	result, err := parsePage([]byte(dial + `<div class="market-fng-gauge__timestamp" data-timestamp="2026-08-11T20:00:00Z">`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rating != "extreme fear" {
+		t.Errorf("Rating = %q, want %q", result.Rating, "extreme fear")
+	}
+	if want := time.Date(2026, 8, 11, 20, 0, 0, 0, time.UTC); !result.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", result.Timestamp, want)
+	}
+
+	// Without its update time the page cannot say when the reading is from.
+	if _, err := parsePage([]byte(dial)); !errors.Is(err, ErrEmptyResult) {
+		t.Errorf("without a time: err = %v, want ErrEmptyResult", err)
+	}
+	if _, err := parsePage([]byte("<html>redesigned</html>")); !errors.Is(err, ErrEmptyResult) {
+		t.Errorf("err = %v, want ErrEmptyResult", err)
+	}
+}
+
+func TestGetAllSourcesFail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	_, err := Get(context.Background())
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("err = %v, want ErrUnexpectedStatus", err)
+	}
+	for _, src := range Sources {
+		if !strings.Contains(err.Error(), src.Name()+":") {
+			t.Errorf("error does not mention source %q: %v", src.Name(), err)
+		}
+	}
+
+	old := Sources
+	Sources = nil
+	defer func() { Sources = old }()
+	if _, err := Get(context.Background()); err == nil {
+		t.Error("Get with no sources succeeded")
+	}
+}
@@ -0,0 +1,103 @@
+package cnnfag
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Stats summarizes a series over a window, as Summarize computes it.
+type Stats struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Count  int       `json:"count"`
+	Mean   float64   `json:"mean"`
+	Median float64   `json:"median"`
+	// Stdev is the population standard deviation of the window.
+	Stdev float64 `json:"stdev"`
+	Min   Value   `json:"min"`
+	Max   Value   `json:"max"`
+	// Latest is the newest value and Percentile its rank in the window:
+	// the percentage of values at or below it.
+	Latest     Value   `json:"latest"`
+	Percentile float64 `json:"percentile"`
+	// BandDays counts the values with each rating.
+	BandDays map[string]int `json:"bandDays"`
+}
+
+// Summarize computes Stats for values, oldest first, as Series and Between
+// return them. Ties for the minimum or maximum go to the earliest date. An
+// empty series gives zero Stats.
+func Summarize(values []Value) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	s := Stats{
+		Start:    values[0].Date,
+		End:      values[len(values)-1].Date,
+		Count:    len(values),
+		Min:      values[0],
+		Max:      values[0],
+		Latest:   values[len(values)-1],
+		BandDays: map[string]int{},
+	}
+
+	sorted := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		sorted[i] = v.Value
+		sum += v.Value
+		if v.Value < s.Min.Value {
+			s.Min = v
+		}
+		if v.Value > s.Max.Value {
+			s.Max = v
+		}
+		s.BandDays[v.Rating]++
+	}
+	s.Mean = sum / float64(len(values))
+
+	var squares float64
+	for _, x := range sorted {
+		squares += (x - s.Mean) * (x - s.Mean)
+	}
+	s.Stdev = math.Sqrt(squares / float64(len(values)))
+
+	sort.Float64s(sorted)
+	if n := len(sorted); n%2 == 1 {
+		s.Median = sorted[n/2]
+	} else {
+		s.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	s.Percentile = PercentileRank(values, s.Latest.Value)
+	return s
+}
+
+// PercentileRank returns the percentage of values at or below x, from 0 to
+// 100: how unusual a reading is against a history. It is 0 for no values.
+func PercentileRank(values []Value, x float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	n := 0
+	for _, v := range values {
+		if v.Value <= x {
+			n++
+		}
+	}
+	return 100 * float64(n) / float64(len(values))
+}
+
+// At returns the value observed on date, or the last one before it when the
+// market was closed that day. values must be oldest first. It reports false
+// when every value is later than date.
+func At(values []Value, date time.Time) (Value, bool) {
+	// The newest value carries the time of the update rather than the start
+	// of the day, so compare against the end of the day asked for.
+	end := date.Truncate(24*time.Hour).AddDate(0, 0, 1)
+	i := sort.Search(len(values), func(i int) bool { return !values[i].Date.Before(end) })
+	if i == 0 {
+		return Value{}, false
+	}
+	return values[i-1], true
+}
@@ -0,0 +1,69 @@
+package cnnfag
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	values := []Value{
+		{Date: day(2), Value: 20, Rating: "extreme fear"},
+		{Date: day(3), Value: 40, Rating: "fear"},
+		{Date: day(4), Value: 80, Rating: "extreme greed"},
+		{Date: day(5), Value: 20, Rating: "extreme fear"},
+		{Date: day(6).Add(15 * time.Hour), Value: 40, Rating: "fear"},
+	}
+
+	s := Summarize(values)
+	if s.Count != 5 || s.Mean != 40 || s.Median != 40 || !s.Start.Equal(day(2)) || !s.End.Equal(values[4].Date) {
+		t.Errorf("Summarize = %+v", s)
+	}
+	if math.Abs(s.Stdev-21.9089) > 1e-4 {
+		t.Errorf("Stdev = %v, want 21.9089", s.Stdev)
+	}
+	if !s.Min.Date.Equal(day(2)) || s.Max.Value != 80 || !s.Max.Date.Equal(day(4)) {
+		t.Errorf("Min = %+v, Max = %+v; want the earliest minimum", s.Min, s.Max)
+	}
+	if s.Latest.Value != 40 || s.Percentile != 80 {
+		t.Errorf("Latest = %+v, Percentile = %v, want 40 at 80", s.Latest, s.Percentile)
+	}
+	if s.BandDays["extreme fear"] != 2 || s.BandDays["fear"] != 2 || s.BandDays["extreme greed"] != 1 || len(s.BandDays) != 3 {
+		t.Errorf("BandDays = %v", s.BandDays)
+	}
+	if got := Summarize(values[:4]).Median; got != 30 {
+		t.Errorf("even median = %v, want 30", got)
+	}
+	if got := Summarize(nil); got.Count != 0 || got.BandDays != nil {
+		t.Errorf("Summarize(nil) = %+v", got)
+	}
+
+	if got := PercentileRank(values, 10); got != 0 {
+		t.Errorf("PercentileRank below all = %v", got)
+	}
+	if got := PercentileRank(values, 100); got != 100 {
+		t.Errorf("PercentileRank above all = %v", got)
+	}
+
+	// At falls back to the previous trading day and matches the newest
+	// value, stamped with its update time, by date.
+	for _, tt := range []struct {
+		date time.Time
+		want float64
+		ok   bool
+	}{
+		{day(1), 0, false},
+		{day(3), 40, true},
+		{day(6), 40, true},
+		{day(9), 40, true},
+	} {
+		v, ok := At(values, tt.date)
+		if ok != tt.ok || v.Value != tt.want {
+			t.Errorf("At(%s) = %v, %v", tt.date.Format(time.DateOnly), v, ok)
+		}
+	}
+	if v, _ := At(values, day(5)); !v.Date.Equal(day(5)) {
+		t.Errorf("At(day 5) = %+v", v)
+	}
+}
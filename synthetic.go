@@ -0,0 +1,195 @@
+package cnnfag
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// SyntheticOptions configures Synthetic. The zero value is usable.
+type SyntheticOptions struct {
+	// Seed selects the random series. Equal options give byte-identical
+	// payloads.
+	Seed int64
+	// Days is the number of trading days in every series. Zero means 252,
+	// about a year, which is what CNN serves.
+	Days int
+	// End is the time of the newest point, CNN's "latest update". The zero
+	// time means now, so set it too when the output must be reproducible.
+	// An End on a weekend moves back to the Friday before.
+	End time.Time
+}
+
+// How each indicator's raw value follows its 0-100 score. from is the raw
+// value at score 0 (extreme fear), to the value at 100; the ranges are what
+// CNN's series have spanned in recent years.
+var syntheticRanges = [...]struct{ from, to, noise float64 }{
+	{0, 0, 0},        // market momentum: an S&P 500 walk, see below
+	{-4, 8, 0.3},     // stock price strength: net new highs, percent
+	{-500, 2000, 40}, // stock price breadth: McClellan summation index
+	{1.2, 0.5, 0.02}, // put/call options: 5-day put/call ratio
+	{40, 11, 0.5},    // market volatility: VIX level
+	{3.2, 0.8, 0.03}, // junk bond demand: junk over investment-grade spread
+	{-6, 6, 0.3},     // safe haven demand: stocks minus bonds, 20-day return
+}
+
+// Synthetic returns a made-up API response in the exact shape CNN serves,
+// for load tests and demos that must not call CNN. Serving it in place of
+// the real endpoint makes Get return it as a Result.
+//
+// The series are plausible rather than realistic: the seven indicator scores
+// wander around a shared mean-reverting sentiment, the index is their
+// average as in CNN's method, raw values follow the scores, and every rating
+// matches its score's band.
+func Synthetic(opts SyntheticOptions) []byte {
+	days := opts.Days
+	if days <= 0 {
+		days = 252
+	}
+	end := opts.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	end = end.UTC()
+	for end.Weekday() == time.Saturday || end.Weekday() == time.Sunday {
+		end = end.AddDate(0, 0, -1)
+	}
+
+	// Trading days, oldest first, ending on end's day.
+	dates := make([]time.Time, days)
+	day := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	for i := days - 1; i >= 0; i-- {
+		for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			day = day.AddDate(0, 0, -1)
+		}
+		dates[i] = day
+		day = day.AddDate(0, 0, -1)
+	}
+	dates[days-1] = end
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var scores [7][]float64
+	for i := range scores {
+		scores[i] = make([]float64, days)
+	}
+	index := make([]float64, days)
+
+	common := 50 + rng.NormFloat64()*12
+	var drift [7]float64
+	for t := 0; t < days; t++ {
+		common += 0.05*(50-common) + rng.NormFloat64()*3
+		sum := 0.0
+		for i := range scores {
+			drift[i] = 0.9*drift[i] + rng.NormFloat64()*4
+			scores[i][t] = clampScore(common + drift[i])
+			sum += scores[i][t]
+		}
+		index[t] = sum / float64(len(scores))
+	}
+
+	var raw syntheticPayload
+	last := days - 1
+	ago := func(n int) float64 { return index[max(last-n, 0)] }
+	raw.FearAndGreed.Score = index[last]
+	raw.FearAndGreed.Rating = RatingOf(index[last])
+	raw.FearAndGreed.Timestamp = end.Format(cnnTimestampLayout)
+	raw.FearAndGreed.PreviousClose = ago(1)
+	raw.FearAndGreed.Previous1W = ago(5)
+	raw.FearAndGreed.Previous1M = ago(21)
+	raw.FearAndGreed.Previous1Y = ago(252)
+	raw.Historical = syntheticSeries(dates, index, index)
+
+	price := 5000 + rng.Float64()*1000
+	momentum := make([]float64, days)
+	for t := range momentum {
+		price *= 1 + (scores[0][t]-50)/50*0.002 + rng.NormFloat64()*0.008
+		momentum[t] = math.Round(price*100) / 100
+	}
+	values := [7][]float64{momentum}
+	for i := 1; i < len(values); i++ {
+		r := syntheticRanges[i]
+		values[i] = make([]float64, days)
+		for t := range values[i] {
+			values[i][t] = r.from + (r.to-r.from)*scores[i][t]/100 + rng.NormFloat64()*r.noise
+		}
+	}
+
+	for i, s := range []*apiSeries{
+		&raw.MarketMomentum, &raw.StockPriceStrength, &raw.StockPriceBreadth,
+		&raw.PutCallOptions, &raw.MarketVolatility, &raw.JunkBondDemand,
+		&raw.SafeHavenDemand,
+	} {
+		*s = syntheticSeries(dates, scores[i], values[i])
+	}
+	// CNN also sends the averages that momentum and volatility are judged
+	// against, scored as their indicators are.
+	raw.MarketMomentum125 = syntheticSeries(dates, scores[0], movingAverage(momentum, 125))
+	raw.MarketVolatility50 = syntheticSeries(dates, scores[4], movingAverage(values[4], 50))
+
+	// Cannot fail: the payload holds only plain fields and finite numbers.
+	data, _ := json.Marshal(raw)
+	return data
+}
+
+// cnnTimestampLayout is how CNN writes fear_and_greed.timestamp: RFC 3339
+// with "+00:00" rather than "Z".
+const cnnTimestampLayout = "2006-01-02T15:04:05.999999-07:00"
+
+// syntheticPayload is CNN's payload as it goes over the wire. apiResponse
+// decodes only what Get uses; this carries every key CNN sends, in CNN's
+// order.
+type syntheticPayload struct {
+	FearAndGreed struct {
+		Score         float64 `json:"score"`
+		Rating        string  `json:"rating"`
+		Timestamp     string  `json:"timestamp"`
+		PreviousClose float64 `json:"previous_close"`
+		Previous1W    float64 `json:"previous_1_week"`
+		Previous1M    float64 `json:"previous_1_month"`
+		Previous1Y    float64 `json:"previous_1_year"`
+	} `json:"fear_and_greed"`
+	Historical         apiSeries `json:"fear_and_greed_historical"`
+	MarketMomentum     apiSeries `json:"market_momentum_sp500"`
+	MarketMomentum125  apiSeries `json:"market_momentum_sp125"`
+	StockPriceStrength apiSeries `json:"stock_price_strength"`
+	StockPriceBreadth  apiSeries `json:"stock_price_breadth"`
+	PutCallOptions     apiSeries `json:"put_call_options"`
+	MarketVolatility   apiSeries `json:"market_volatility_vix"`
+	MarketVolatility50 apiSeries `json:"market_volatility_vix_50"`
+	JunkBondDemand     apiSeries `json:"junk_bond_demand"`
+	SafeHavenDemand    apiSeries `json:"safe_haven_demand"`
+}
+
+// movingAverage returns the trailing n-day average of values, over fewer
+// days at the start where there are not n yet.
+func movingAverage(values []float64, n int) []float64 {
+	avg := make([]float64, len(values))
+	sum := 0.0
+	for t, v := range values {
+		sum += v
+		if t >= n {
+			sum -= values[t-n]
+		}
+		avg[t] = sum / float64(min(t+1, n))
+	}
+	return avg
+}
+
+func syntheticSeries(dates []time.Time, scores, values []float64) apiSeries {
+	last := len(dates) - 1
+	s := apiSeries{
+		Timestamp: float64(dates[last].UnixMilli()),
+		Score:     scores[last],
+		Rating:    RatingOf(scores[last]),
+		Data:      make([]apiPoint, len(dates)),
+	}
+	for t, d := range dates {
+		s.Data[t] = apiPoint{X: float64(d.UnixMilli()), Y: values[t], Rating: RatingOf(scores[t])}
+	}
+	return s
+}
+
+func clampScore(s float64) float64 {
+	return math.Min(math.Max(s, 0.5), 99.5)
+}
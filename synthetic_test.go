@@ -0,0 +1,118 @@
+package cnnfag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSynthetic(t *testing.T) {
+	end := time.Date(2026, 8, 11, 20, 0, 0, 0, time.UTC)
+	payload := Synthetic(SyntheticOptions{Seed: 7, Days: 30, End: end})
+
+	if !bytes.Equal(payload, Synthetic(SyntheticOptions{Seed: 7, Days: 30, End: end})) {
+		t.Error("equal options gave different payloads")
+	}
+	if bytes.Equal(payload, Synthetic(SyntheticOptions{Seed: 8, Days: 30, End: end})) {
+		t.Error("different seeds gave the same payload")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer srv.Close()
+
+	useServer(t, srv.URL)
+
+	result, err := Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Timestamp.Equal(end) {
+		t.Errorf("Timestamp = %v, want %v", result.Timestamp, end)
+	}
+	if len(result.History) != 30 {
+		t.Fatalf("len(History) = %d, want 30", len(result.History))
+	}
+	newest := result.History[len(result.History)-1]
+	if newest.Score != result.Score || !newest.Date.Equal(end) {
+		t.Errorf("newest point = %+v, want score %v at %v", newest, result.Score, end)
+	}
+	if result.PreviousClose != result.History[28].Score {
+		t.Errorf("PreviousClose = %v, want %v", result.PreviousClose, result.History[28].Score)
+	}
+	for _, p := range result.History {
+		if p.Score <= 0 || p.Score >= 100 {
+			t.Errorf("score %v out of range on %v", p.Score, p.Date)
+		}
+		if p.Rating != RatingOf(p.Score) {
+			t.Errorf("rating %q does not match score %v", p.Rating, p.Score)
+		}
+		if wd := p.Date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			t.Errorf("point on a weekend: %v", p.Date)
+		}
+	}
+
+	vix := result.MarketVolatility
+	if len(vix.History) != 30 || vix.Rating != RatingOf(vix.Score) {
+		t.Errorf("MarketVolatility = %v (%s) with %d points", vix.Score, vix.Rating, len(vix.History))
+	}
+	for _, v := range vix.History {
+		if v.Value < 5 || v.Value > 50 {
+			t.Errorf("VIX level %v is implausible", v.Value)
+		}
+	}
+	if sp := result.MarketMomentum.History[0].Value; sp < 3000 || sp > 8000 {
+		t.Errorf("S&P 500 level %v is implausible", sp)
+	}
+}
+
+func TestSyntheticShape(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Sunday: the newest point moves back to Friday.
+	payload := Synthetic(SyntheticOptions{Seed: 1, Days: 10, End: time.Date(2026, 8, 16, 20, 0, 0, 0, time.UTC)})
+
+	keys := func(data []byte) (top []string, fearAndGreed []string, timestamp string) {
+		t.Helper()
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatal(err)
+		}
+		var fg map[string]json.RawMessage
+		if err := json.Unmarshal(m["fear_and_greed"], &fg); err != nil {
+			t.Fatal(err)
+		}
+		for k := range m {
+			top = append(top, k)
+		}
+		for k := range fg {
+			fearAndGreed = append(fearAndGreed, k)
+		}
+		sort.Strings(top)
+		sort.Strings(fearAndGreed)
+		json.Unmarshal(fg["timestamp"], &timestamp)
+		return top, fearAndGreed, timestamp
+	}
+	wantTop, wantFG, _ := keys(fixture)
+	gotTop, gotFG, timestamp := keys(payload)
+	if !reflect.DeepEqual(gotTop, wantTop) {
+		t.Errorf("top-level keys = %v, want CNN's %v", gotTop, wantTop)
+	}
+	if !reflect.DeepEqual(gotFG, wantFG) {
+		t.Errorf("fear_and_greed keys = %v, want CNN's %v", gotFG, wantFG)
+	}
+	if timestamp != "2026-08-14T20:00:00+00:00" {
+		t.Errorf("timestamp = %q, want Friday's in CNN's format", timestamp)
+	}
+}
@@ -0,0 +1,29 @@
+package cnnfag
+
+import "context"
+
+// Trace holds hooks Get calls as it walks Sources, for logging and progress
+// reporting. Any hook may be nil. Attach a Trace to a context with
+// WithTrace, as net/http/httptrace does for requests.
+type Trace struct {
+	// SourceStart is called before Get tries a source.
+	SourceStart func(name string)
+	// SourceDone is called after a source returns, with its error if it
+	// failed. A failure followed by another SourceStart is a fallback.
+	SourceDone func(name string, err error)
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context that makes Get report to t.
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// contextTrace returns the Trace attached to ctx, or an empty one.
+func contextTrace(ctx context.Context) *Trace {
+	if t, ok := ctx.Value(traceKey{}).(*Trace); ok {
+		return t
+	}
+	return &Trace{}
+}
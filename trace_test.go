@@ -0,0 +1,45 @@
+package cnnfag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/graphdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("User-Agent"), "Firefox") {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+	useServer(t, srv.URL)
+
+	var events []string
+	ctx := WithTrace(context.Background(), &Trace{
+		SourceStart: func(name string) { events = append(events, "start "+name) },
+		SourceDone:  func(name string, err error) { events = append(events, fmt.Sprintf("done %s %v", name, err)) },
+	})
+	if _, err := Get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := "start graphdata|done graphdata unexpected http status: 418|start graphdata-firefox|done graphdata-firefox <nil>"
+	if got := strings.Join(events, "|"); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+
+	// Nil hooks and a context without a Trace are fine.
+	if _, err := Get(WithTrace(context.Background(), &Trace{})); err != nil {
+		t.Fatal(err)
+	}
+}